@@ -0,0 +1,90 @@
+// Command fetchheaders captures consecutive block headers from a JSON-RPC
+// endpoint as a conformance fixture
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/conformance"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/hexutil"
+)
+
+func main() {
+	rpcURL := flag.String("rpc", "", "JSON-RPC endpoint of an archive node")
+	chain := flag.String("chain", "mainnet", "Chain preset the headers belong to")
+	from := flag.Uint64("from", 12_964_990, "First block number")
+	to := flag.Uint64("to", 12_965_010, "Last block number")
+	description := flag.String("description", "", "Fixture description")
+	flag.Parse()
+
+	if *rpcURL == "" || *to < *from {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fixture := conformance.Fixture{
+		Description: *description,
+		Chain:       *chain,
+	}
+	if fixture.Description == "" {
+		fixture.Description = fmt.Sprintf("%s blocks %d-%d captured from JSON-RPC", *chain, *from, *to)
+	}
+
+	for number := *from; number <= *to; number++ {
+		header, err := fetchHeader(*rpcURL, number)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "block %d: %v\n", number, err)
+			os.Exit(1)
+		}
+		fixture.Headers = append(fixture.Headers, *header)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(fixture); err != nil {
+		fmt.Fprintf(os.Stderr, "encode: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// fetchHeader calls eth_getBlockByNumber without transaction bodies
+func fetchHeader(rpcURL string, number uint64) (*conformance.Header, error) {
+	request, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_getBlockByNumber",
+		"params":  []any{hexutil.EncodeUint64(number), false},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Post(rpcURL, "application/json", bytes.NewReader(request))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Result *conformance.Header `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("rpc error %d: %s", response.Error.Code, response.Error.Message)
+	}
+	if response.Result == nil {
+		return nil, fmt.Errorf("block not found")
+	}
+	return response.Result, nil
+}
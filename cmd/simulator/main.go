@@ -3,51 +3,129 @@ package main
 import (
 	"flag"
 	"fmt"
+	"math/rand/v2"
+	"os"
 
 	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
 	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
 	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
 	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
 	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/crypto"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
 )
 
 func main() {
 	// Command line flags
 	blocks := flag.Int("blocks", 10, "Number of blocks to simulate")
-	gasUsed := flag.Uint64("gas", 15000000, "Gas used per block (target is 15M)")
+	gasUsed := flag.Uint64("gas", 15000000, "Gas used per block (mainnet target is 15M)")
+	chain := flag.String("chain", "mainnet", "Chain preset: mainnet, optimism or base")
+	ruleName := flag.String("rule", "eip1559", "Base fee rule: eip1559, exponential, aimd, pid, moving-average or time-aware")
+	var minBaseFee, maxBaseFee *uint256.Int
+	flag.Func("min-basefee", "Base fee floor in wei (default none)", weiFlag(&minBaseFee))
+	flag.Func("max-basefee", "Base fee ceiling in wei (default none)", weiFlag(&maxBaseFee))
+	missedSlots := flag.Float64("missed-slots", 0, "Probability that a slot is missed (0 to 1)")
+	seed := flag.Uint64("seed", 1, "Random seed for missed slots")
 	verbose := flag.Bool("verbose", false, "Verbose output")
+	crossCheck := flag.Bool("crosscheck", false, "Check every base fee against all registered implementations")
+	replayChain := flag.String("replay", "", "Second chain preset that every transaction is replayed on, to show EIP-155 replay protection")
 	flag.Parse()
 
+	preset, ok := constants.ChainConfigs[*chain]
+	if !ok {
+		fmt.Printf("Unknown chain %q\n", *chain)
+		return
+	}
+	if *missedSlots < 0 || *missedSlots >= 1 {
+		fmt.Printf("Missed slot probability must be in [0, 1), got %g\n", *missedSlots)
+		return
+	}
+	// Copy the preset so the bounds don't leak into the shared value
+	bounded := *preset
+	bounded.MinBaseFee, bounded.MaxBaseFee = minBaseFee, maxBaseFee
+	config := &bounded
+	if err := config.Validate(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	newRule, ok := basefee.Rules[*ruleName]
+	if !ok {
+		fmt.Printf("Unknown base fee rule %q\n", *ruleName)
+		return
+	}
+	rule := newRule()
+	var replayConfig *constants.ChainConfig
+	if *replayChain != "" {
+		if replayConfig, ok = constants.ChainConfigs[*replayChain]; !ok {
+			fmt.Printf("Unknown replay chain %q\n", *replayChain)
+			return
+		}
+	}
+
 	fmt.Println("EIP-1559 Simulator")
 	fmt.Println("=====================")
+	fmt.Printf("Chain: %s (denominator %d, elasticity %d)\n",
+		config.Name, config.BaseFeeChangeDenominator, config.ElasticityMultiplier)
+	fmt.Printf("Base fee rule: %s\n", rule.Name())
+	if config.MinBaseFee != nil || config.MaxBaseFee != nil {
+		fmt.Printf("Base fee bounds: floor %s wei, ceiling %s wei\n", weiOrNone(config.MinBaseFee), weiOrNone(config.MaxBaseFee))
+	}
 	fmt.Printf("Simulating %d blocks with %d gas used per block\n\n", *blocks, *gasUsed)
 
 	// Initialize state
 	state := types.NewState()
 
 	// Create initial accounts
-	minerAddr := "0xMiner"
-	senderAddr := "0xAlice"
-	recipientAddr := "0xBob"
+	minerAddr := types.HexToAddress("0x0000000000000000000000000000000000c0ffee")
+	// The sender signs every transaction with a fixed, well-known key
+	senderKey, err := crypto.HexToPrivateKey("0x00000000000000000000000000000000000000000000000000000000000a11ce")
+	if err != nil {
+		fmt.Printf("Invalid sender key: %v\n", err)
+		return
+	}
+	senderAddr := types.PubkeyToAddress(senderKey.PublicKey)
+	recipientAddr := types.HexToAddress("0x0000000000000000000000000000000000000b0b")
 
-	state.SetAccount(minerAddr, types.NewAccount(minerAddr, 0))
+	state.SetAccount(minerAddr, types.NewAccount(minerAddr, uint256.Zero))
 	// Give sender enough balance to cover several transactions (in wei)
-	state.SetAccount(senderAddr, types.NewAccount(senderAddr, 1_000_000_000_000_000))
-	state.SetAccount(recipientAddr, types.NewAccount(recipientAddr, 0))
+	state.SetAccount(senderAddr, types.NewAccount(senderAddr, uint256.NewInt(1_000_000_000_000_000)))
+	state.SetAccount(recipientAddr, types.NewAccount(recipientAddr, uint256.Zero))
 
-	// Create genesis block
+	// The replay chain runs in the same process with its own state, where the
+	// sender holds the same balance, so only the chain ID protects it
+	replayState := types.NewState()
+	replayState.SetAccount(senderAddr, types.NewAccount(senderAddr, uint256.NewInt(1_000_000_000_000_000)))
+	var replayHead *types.Block
+	if replayConfig != nil {
+		replayHead = &types.Block{
+			Number:   max(replayConfig.LondonBlock, 1),
+			GasLimit: 30_000_000,
+			BaseFee:  replayConfig.InitialBaseFee,
+			Miner:    minerAddr,
+		}
+	}
+	replayed, rejected := 0, 0
+	var replayErr error
+
+	// Create genesis block (the block before London, or block 0 on chains with London at genesis)
 	genesisBlock := &types.Block{
-		Number:   constants.ForkBlockNumber - 1,
-		Hash:     "0xgenesis",
-		GasLimit: 30_000_000,
-		GasUsed:  15_000_000,
-		BaseFee:  constants.InitialBaseFee,
-		Miner:    minerAddr,
+		Number:    max(config.LondonBlock, 1) - 1,
+		GasLimit:  30_000_000,
+		BaseFee:   config.InitialBaseFee,
+		Miner:     minerAddr,
+		Timestamp: 1_700_000_000,
 	}
+	if !config.IsLondon(genesisBlock.Number) {
+		// A pre-London parent has no base fee, and the fork block scales its gas limit by the elasticity multiplier
+		genesisBlock.GasLimit /= config.ElasticityMultiplier
+		genesisBlock.BaseFee = uint256.Zero
+	}
+	genesisBlock.GasUsed = genesisBlock.GasTarget(config)
 
 	currentBlock := genesisBlock
-	totalBurned := uint64(0)
-	totalTips := uint64(0)
+	rng := rand.New(rand.NewPCG(*seed, *seed))
+	totalBurned := uint256.Zero
+	totalTips := uint256.Zero
 
 	fmt.Printf("%-6s | %-12s | %-12s | %-8s | %-12s | %-12s\n",
 		"Block", "BaseFee", "GasUsed", "Usage%", "Burned", "Tips")
@@ -55,34 +133,51 @@ func main() {
 
 	// Simulate blocks
 	for i := 0; i < *blocks; i++ {
+		// Each block lands in the next slot that isn't missed
+		missed := uint64(0)
+		for rng.Float64() < *missedSlots {
+			missed++
+		}
+		timestamp := currentBlock.Timestamp + (missed+1)*constants.SecondsPerSlot
+
 		// Calculate next base fee
-		nextBaseFee := basefee.Calculate(currentBlock)
+		nextBaseFee := basefee.NextBaseFee(rule, config, currentBlock, timestamp)
+		if *crossCheck {
+			if err := basefee.CheckParent(config, currentBlock); err != nil {
+				fmt.Printf("Cross-check failed: %v\n", err)
+				os.Exit(1)
+			}
+		}
 
 		// Create next block
 		nextBlock := types.NewBlock(
 			currentBlock.Number+1,
-			currentBlock.Hash,
+			currentBlock.Hash(),
 			30_000_000,
 			nextBaseFee,
 			minerAddr,
 		)
-		nextBlock.Hash = fmt.Sprintf("0xblock%d", nextBlock.Number)
+		nextBlock.Timestamp = timestamp
 
 		// Create transaction
 		tx := &types.Transaction{
-			ChainID:              1,
+			Type:                 types.DynamicFeeTxType,
+			ChainID:              config.ChainID,
 			Nonce:                state.GetNonce(senderAddr),
-			MaxPriorityFeePerGas: 2_000_000_000,               // 2 Gwei tip
-			MaxFeePerGas:         nextBaseFee + 5_000_000_000, // base fee + 5 Gwei
+			MaxPriorityFeePerGas: uint256.NewInt(2_000_000_000),                  // 2 Gwei tip
+			MaxFeePerGas:         nextBaseFee.Add(uint256.NewInt(5_000_000_000)), // base fee + 5 Gwei
 			// Use a realistic per-transaction gas limit (transfer ~21k)
 			GasLimit: 21_000,
-			To:       recipientAddr,
-			Value:    1_000,
-			From:     senderAddr,
+			To:       &recipientAddr,
+			Value:    uint256.NewInt(1_000),
+		}
+		if err := tx.Sign(senderKey); err != nil {
+			fmt.Printf("Transaction signing failed: %v\n", err)
+			continue
 		}
 
 		// Validate transaction
-		if err := validator.ValidateTransaction(tx, nextBaseFee, state); err != nil {
+		if err := validator.ValidateTransaction(config, tx, nextBlock, state); err != nil {
 			fmt.Printf("Transaction validation failed: %v\n", err)
 			continue
 		}
@@ -93,21 +188,45 @@ func main() {
 			continue
 		}
 
-		// Validate block
-		if err := validator.ValidateBlock(nextBlock, currentBlock); err != nil {
-			fmt.Printf("Block validation failed: %v\n", err)
+		// Execute the block
+		snapshot := state.Snapshot()
+		receipts, err := executor.ExecuteBlock(nextBlock, state)
+		if err != nil {
+			fmt.Printf("Transaction execution failed: %v\n", err)
 			continue
 		}
+		result := receipts[0]
 
-		// Execute transaction
-		result := executor.ExecuteTransaction(tx, nextBlock, state)
-		if !result.Success {
-			fmt.Printf("Transaction execution failed: %v\n", result.Error)
+		// Seal the gas actually used, then validate the block against its parent and receipts.
+		// A rejected block is rolled back; an accepted one is committed.
+		if err := executor.SealBlock(nextBlock, receipts); err != nil {
+			fmt.Printf("Sealing failed: %v\n", err)
+			state.RevertToSnapshot(snapshot)
 			continue
 		}
+		if err := validator.ValidateExecutedBlock(config, rule, nextBlock, currentBlock, timestamp, receipts); err != nil {
+			fmt.Printf("Block validation failed: %v\n", err)
+			state.RevertToSnapshot(snapshot)
+			continue
+		}
+		state.Commit()
 
-		totalBurned += result.BaseFeeAmount
-		totalTips += result.TipAmount
+		// Replay the transaction on the second chain, as an attacker would
+		if replayConfig != nil {
+			replayed++
+			if err := validator.ValidateTransaction(replayConfig, tx, replayHead, replayState); err != nil {
+				rejected++
+				if replayErr == nil {
+					replayErr = err
+				}
+			} else if result := executor.ExecuteTransaction(tx, replayHead, replayState); !result.Success {
+				fmt.Printf("Replayed transaction failed: %v\n", result.Error)
+			}
+			replayState.Commit()
+		}
+
+		totalBurned = totalBurned.Add(result.BaseFeeAmount)
+		totalTips = totalTips.Add(result.TipAmount)
 
 		// Print block info
 		utilization := nextBlock.Utilization()
@@ -119,6 +238,9 @@ func main() {
 			result.BaseFeeAmount,
 			result.TipAmount,
 		)
+		if missed > 0 {
+			fmt.Printf("       (after %d missed slot(s), %ds since parent)\n", missed, nextBlock.Timestamp-currentBlock.Timestamp)
+		}
 
 		if *verbose {
 			fmt.Printf("  Sender balance: %d\n", state.GetBalance(senderAddr))
@@ -138,9 +260,38 @@ func main() {
 	fmt.Printf("Total tips paid:  %d wei\n", totalTips)
 	fmt.Printf("Final base fee:   %d wei (%.2f Gwei)\n",
 		currentBlock.BaseFee,
-		float64(currentBlock.BaseFee)/1_000_000_000)
+		currentBlock.BaseFee.Float64()/1_000_000_000)
 	fmt.Printf("\nFinal balances:\n")
 	fmt.Printf("  Alice (sender):    %d wei\n", state.GetBalance(senderAddr))
 	fmt.Printf("  Bob (recipient):   %d wei\n", state.GetBalance(recipientAddr))
 	fmt.Printf("  Miner:             %d wei\n", state.GetBalance(minerAddr))
+
+	if replayConfig != nil {
+		fmt.Printf("\nReplay on %s (chain ID %d):\n", replayConfig.Name, replayConfig.ChainID)
+		fmt.Printf("  Replayed: %d, rejected: %d\n", replayed, rejected)
+		if replayErr != nil {
+			fmt.Printf("  First rejection: %v\n", replayErr)
+		}
+		fmt.Printf("  Sender balance there: %d wei\n", replayState.GetBalance(senderAddr))
+	}
+}
+
+// weiFlag parses a decimal wei amount flag into dst
+func weiFlag(dst **uint256.Int) func(string) error {
+	return func(s string) error {
+		v, err := uint256.FromDecimal(s)
+		if err != nil {
+			return err
+		}
+		*dst = &v
+		return nil
+	}
+}
+
+// weiOrNone formats an optional bound
+func weiOrNone(v *uint256.Int) string {
+	if v == nil {
+		return "none"
+	}
+	return v.String()
 }
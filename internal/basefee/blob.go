@@ -0,0 +1,57 @@
+package basefee
+
+import (
+	"math/big"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// CalculateBlobBaseFee returns the blob base fee (wei per blob gas) for a block with the given excess blob gas
+func CalculateBlobBaseFee(excessBlobGas uint64) uint256.Int {
+	return FakeExponential(
+		uint256.NewInt(constants.MinBlobBaseFee),
+		uint256.NewInt(excessBlobGas),
+		uint256.NewInt(constants.BlobBaseFeeUpdateFraction),
+	)
+}
+
+// CalculateExcessBlobGas returns the excess blob gas of the block following parent:
+// the blob gas used above target, carried forward from block to block. It fails with
+// types.ErrGasUintOverflow if the parent's excess and used blob gas overflow a uint64.
+func CalculateExcessBlobGas(parent *types.Block) (uint64, error) {
+	total, err := types.SafeAddGas(parent.ExcessBlobGas, parent.BlobGasUsed)
+	if err != nil {
+		return 0, err
+	}
+	if total < constants.TargetBlobGasPerBlock {
+		return 0, nil
+	}
+	return total - constants.TargetBlobGasPerBlock, nil
+}
+
+// FakeExponential approximates factor * e^(numerator / denominator) using the
+// integer Taylor expansion from EIP-4844. The result saturates at 2^256 - 1.
+func FakeExponential(factor, numerator, denominator uint256.Int) uint256.Int {
+	if denominator.IsZero() {
+		return uint256.Zero
+	}
+
+	num, denom := numerator.ToBig(), denominator.ToBig()
+	limit := new(big.Int).Mul(uint256.Max.ToBig(), denom) // output/denom above this no longer fits
+	output := new(big.Int)
+	accum := new(big.Int).Mul(factor.ToBig(), denom)
+	for i := int64(1); accum.Sign() > 0; i++ {
+		output.Add(output, accum)
+		if output.Cmp(limit) > 0 {
+			return uint256.Max
+		}
+
+		accum.Mul(accum, num)
+		accum.Div(accum, new(big.Int).Mul(denom, big.NewInt(i)))
+	}
+	output.Div(output, denom)
+
+	return uint256.MustFromBig(output)
+}
@@ -1,66 +1,96 @@
 package basefee
 
 import (
+	"math/big"
+
 	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
 	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
 )
 
 // Calculate computes the base fee for the next block based on parent block
-func Calculate(parent *types.Block) uint64 {
-	// Special case: fork block
-	if parent.Number+1 == constants.ForkBlockNumber {
-		return constants.InitialBaseFee
+func Calculate(config *constants.ChainConfig, parent *types.Block) uint256.Int {
+	// Special cases: no base fee before the fork, the initial base fee on the fork block
+	if fee, ok := forkBaseFee(config, parent); ok {
+		return fee
 	}
 
-	parentGasTarget := parent.GasLimit / constants.ElasticityMultiplier
+	return withinBounds(config, update(config, parent, parent.GasTarget(config)))
+}
+
+// update applies the EIP-1559 formula with gas used measured against
+// parentGasTarget; the change is still scaled by the parent's own gas target
+func update(config *constants.ChainConfig, parent *types.Block, parentGasTarget uint64) uint256.Int {
+	return updateFee(parent.BaseFee, parent.GasUsed, parentGasTarget, parent.GasTarget(config), config.BaseFeeChangeDenominator)
+}
 
+// updateFee moves baseFee by (gasUsed - gasTarget) / scaleTarget / denominator
+func updateFee(baseFee uint256.Int, gasUsed, gasTarget, scaleTarget, changeDenominator uint64) uint256.Int {
 	// If parent block used exactly the target, base fee stays the same
-	if parent.GasUsed == parentGasTarget {
-		return parent.BaseFee
+	if gasUsed == gasTarget {
+		return baseFee
 	}
 
-	var newBaseFee uint64
+	target := uint256.NewInt(scaleTarget)
+	denominator := uint256.NewInt(changeDenominator)
 
-	if parent.GasUsed > parentGasTarget {
+	var newBaseFee uint256.Int
+
+	if gasUsed > gasTarget {
 		// Block used more than target - increase base fee
-		gasUsedDelta := parent.GasUsed - parentGasTarget
-		baseFeePerGasDelta := max(
-			(parent.BaseFee*gasUsedDelta)/parentGasTarget/constants.BaseFeeChangeDenominator,
-			1, // Minimum increase of 1 wei
+		gasUsedDelta := uint256.NewInt(gasUsed - gasTarget)
+		baseFeePerGasDelta := uint256.MaxOf(
+			mulDiv(baseFee, gasUsedDelta, target, denominator),
+			uint256.NewInt(1), // Minimum increase of 1 wei
 		)
-		newBaseFee = parent.BaseFee + baseFeePerGasDelta
+		var overflow bool
+		if newBaseFee, overflow = baseFee.AddOverflow(baseFeePerGasDelta); overflow {
+			newBaseFee = uint256.Max
+		}
 	} else {
 		// Block used less than target - decrease base fee
-		gasUsedDelta := parentGasTarget - parent.GasUsed
-		baseFeePerGasDelta := (parent.BaseFee * gasUsedDelta) / parentGasTarget / constants.BaseFeeChangeDenominator
+		gasUsedDelta := uint256.NewInt(gasTarget - gasUsed)
+		baseFeePerGasDelta := mulDiv(baseFee, gasUsedDelta, target, denominator)
 
 		// Ensure base fee doesn't go negative
-		if baseFeePerGasDelta > parent.BaseFee {
-			newBaseFee = 0
+		if baseFeePerGasDelta.Gt(baseFee) {
+			newBaseFee = uint256.Zero
 		} else {
-			newBaseFee = parent.BaseFee - baseFeePerGasDelta
+			newBaseFee = baseFee.Sub(baseFeePerGasDelta)
 		}
 	}
 
 	return newBaseFee
 }
 
-// max returns the larger of two uint64 values
-func max(a, b uint64) uint64 {
-	if a > b {
-		return a
+// mulDiv returns x * y / d1 / d2 without losing the high bits of the product,
+// saturating at uint256.Max. Division by zero yields 0 as in the EVM.
+func mulDiv(x, y, d1, d2 uint256.Int) uint256.Int {
+	product, overflow := x.MulOverflow(y)
+	if !overflow {
+		return product.Div(d1).Div(d2)
+	}
+	if d1.IsZero() || d2.IsZero() {
+		return uint256.Zero
+	}
+	q := new(big.Int).Mul(x.ToBig(), y.ToBig())
+	q.Quo(q, d1.ToBig())
+	q.Quo(q, d2.ToBig())
+	result, overflow := uint256.FromBig(q)
+	if overflow {
+		return uint256.Max
 	}
-	return b
+	return result
 }
 
 // CalculateForBlocks simulates base fee changes over multiple blocks
-func CalculateForBlocks(initialBlock *types.Block, gasUsedSequence []uint64) []uint64 {
-	baseFees := make([]uint64, len(gasUsedSequence))
+func CalculateForBlocks(config *constants.ChainConfig, initialBlock *types.Block, gasUsedSequence []uint64) []uint256.Int {
+	baseFees := make([]uint256.Int, len(gasUsedSequence))
 	currentBlock := initialBlock
 
 	for i, gasUsed := range gasUsedSequence {
 		// Calculate next base fee
-		nextBaseFee := Calculate(currentBlock)
+		nextBaseFee := Calculate(config, currentBlock)
 		baseFees[i] = nextBaseFee
 
 		// Create next block for simulation
@@ -0,0 +1,146 @@
+package basefee
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// Func computes the base fee of the block following parent
+type Func func(config *constants.ChainConfig, parent *types.Block) uint256.Int
+
+// ErrMismatch is returned when a registered implementation disagrees with Calculate
+var ErrMismatch = errors.New("base fee implementations disagree")
+
+// MismatchError reports the first block where an implementation diverged from the reference
+type MismatchError struct {
+	Implementation string
+	Number         uint64 // Block whose base fee was computed
+	Expected       uint256.Int
+	Got            uint256.Int
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%v: %s at block %d: expected %s, got %s", ErrMismatch, e.Implementation, e.Number, e.Expected, e.Got)
+}
+
+func (e *MismatchError) Unwrap() error { return ErrMismatch }
+
+// implementations are checked against Calculate, the reference used by the validator and simulator
+var implementations = map[string]Func{
+	"eip1559-spec": specCalculate,
+}
+
+// Register adds an implementation to the cross-check. It panics if name is already registered.
+func Register(name string, fn Func) {
+	if _, dup := implementations[name]; dup {
+		panic("basefee: implementation registered twice: " + name)
+	}
+	implementations[name] = fn
+}
+
+// Unregister removes an implementation from the cross-check
+func Unregister(name string) {
+	delete(implementations, name)
+}
+
+// Implementations returns the registered implementation names in sorted order
+func Implementations() []string {
+	names := make([]string, 0, len(implementations))
+	for name := range implementations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckParent computes the next base fee with every registered implementation and
+// returns a MismatchError for the first one that disagrees with Calculate
+func CheckParent(config *constants.ChainConfig, parent *types.Block) error {
+	expected := Calculate(config, parent)
+	for _, name := range Implementations() {
+		if got := implementations[name](config, parent); got != expected {
+			return &MismatchError{Implementation: name, Number: parent.Number + 1, Expected: expected, Got: got}
+		}
+	}
+	return nil
+}
+
+// CrossCheck replays gasUsedSequence from initialBlock as CalculateForBlocks does,
+// checking every registered implementation at every block
+func CrossCheck(config *constants.ChainConfig, initialBlock *types.Block, gasUsedSequence []uint64) error {
+	currentBlock := initialBlock
+	for _, gasUsed := range gasUsedSequence {
+		if err := CheckParent(config, currentBlock); err != nil {
+			return err
+		}
+		currentBlock = &types.Block{
+			Number:   currentBlock.Number + 1,
+			GasLimit: currentBlock.GasLimit,
+			GasUsed:  gasUsed,
+			BaseFee:  Calculate(config, currentBlock),
+		}
+	}
+	return nil
+}
+
+// specCalculate is a line-by-line transcription of the EIP-1559 specification using
+// arbitrary-precision integers. It is deliberately independent of Calculate so the
+// cross-check can catch overflow and rounding bugs in the uint256 implementation.
+func specCalculate(config *constants.ChainConfig, parent *types.Block) uint256.Int {
+	if parent.Number+1 < config.LondonBlock {
+		return uint256.Zero
+	}
+	if parent.Number+1 == config.LondonBlock {
+		return config.InitialBaseFee
+	}
+
+	parentBaseFee := parent.BaseFee.ToBig()
+	parentGasTarget := parent.GasLimit / config.ElasticityMultiplier
+
+	// Valid chains never have a zero target (MinGasLimit); follow uint256 division by zero, which yields 0
+	div := func(x *big.Int, y uint64) *big.Int {
+		if y == 0 {
+			return new(big.Int)
+		}
+		return x.Div(x, new(big.Int).SetUint64(y))
+	}
+
+	var expected *big.Int
+	if parent.GasUsed == parentGasTarget {
+		expected = parentBaseFee
+	} else if parent.GasUsed > parentGasTarget {
+		gasUsedDelta := new(big.Int).SetUint64(parent.GasUsed - parentGasTarget)
+		delta := div(div(new(big.Int).Mul(parentBaseFee, gasUsedDelta), parentGasTarget), config.BaseFeeChangeDenominator)
+		if delta.Cmp(big.NewInt(1)) < 0 {
+			delta.SetInt64(1)
+		}
+		expected = new(big.Int).Add(parentBaseFee, delta)
+	} else {
+		gasUsedDelta := new(big.Int).SetUint64(parentGasTarget - parent.GasUsed)
+		delta := div(div(new(big.Int).Mul(parentBaseFee, gasUsedDelta), parentGasTarget), config.BaseFeeChangeDenominator)
+		expected = new(big.Int).Sub(parentBaseFee, delta)
+	}
+
+	if config.MinBaseFee != nil {
+		if floor := config.MinBaseFee.ToBig(); expected.Cmp(floor) < 0 {
+			expected = floor
+		}
+	}
+	if config.MaxBaseFee != nil {
+		if ceiling := config.MaxBaseFee.ToBig(); expected.Cmp(ceiling) > 0 {
+			expected = ceiling
+		}
+	}
+
+	result, overflow := uint256.FromBig(expected)
+	if overflow {
+		return uint256.Max
+	}
+	return result
+}
@@ -0,0 +1,138 @@
+package basefee
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+var (
+	// ErrInsufficientHistory is returned when there are too few blocks to fit a gas usage model
+	ErrInsufficientHistory = errors.New("insufficient block history")
+
+	// ErrInvalidForecastArgs is returned when a forecast asks for no blocks or no paths
+	ErrInvalidForecastArgs = errors.New("invalid forecast arguments")
+)
+
+// GasModel is an AR(1) model of block utilization (gas used / gas limit): each
+// block's utilization is pulled towards Mean with lag-1 correlation
+// Autocorrelation, plus Gaussian noise, so congestion persists for a few blocks
+// the way it does on mainnet
+type GasModel struct {
+	Mean            float64
+	StdDev          float64
+	Autocorrelation float64
+}
+
+// FitGasModel estimates a GasModel from consecutive blocks, oldest first
+func FitGasModel(blocks []*types.Block) (*GasModel, error) {
+	utilization := make([]float64, 0, len(blocks))
+	for _, block := range blocks {
+		if block.GasLimit > 0 {
+			utilization = append(utilization, float64(block.GasUsed)/float64(block.GasLimit))
+		}
+	}
+	if len(utilization) < 2 {
+		return nil, ErrInsufficientHistory
+	}
+
+	var mean float64
+	for _, u := range utilization {
+		mean += u
+	}
+	mean /= float64(len(utilization))
+
+	var variance, covariance float64
+	for i, u := range utilization {
+		variance += (u - mean) * (u - mean)
+		if i > 0 {
+			covariance += (u - mean) * (utilization[i-1] - mean)
+		}
+	}
+
+	model := &GasModel{Mean: mean, StdDev: math.Sqrt(variance / float64(len(utilization)))}
+	if variance > 0 {
+		model.Autocorrelation = max(min(covariance/variance, 1), -1)
+	}
+	return model, nil
+}
+
+// Next draws the utilization of the block after one with utilization previous, clamped to [0, 1]
+func (m *GasModel) Next(rng *rand.Rand, previous float64) float64 {
+	noise := m.StdDev * math.Sqrt(1-m.Autocorrelation*m.Autocorrelation) * rng.NormFloat64()
+	return max(min(m.Mean+m.Autocorrelation*(previous-m.Mean)+noise, 1), 0)
+}
+
+// Band is the forecast base fee distribution for one future block
+type Band struct {
+	Number uint64
+	P10    uint256.Int
+	P50    uint256.Int // Median
+	P90    uint256.Int
+}
+
+// Forecast projects the base fee blocksAhead blocks past the last of recent by
+// simulating paths gas usage paths from a GasModel fitted to recent (oldest
+// first) and returns the p10/p50/p90 band of each future block. At 12-second
+// slots, 25 blocks ahead is the fee in about five minutes.
+func Forecast(config *constants.ChainConfig, recent []*types.Block, blocksAhead, paths int, rng *rand.Rand) ([]Band, error) {
+	if blocksAhead <= 0 || paths <= 0 {
+		return nil, fmt.Errorf("%w: %d blocks ahead, %d paths", ErrInvalidForecastArgs, blocksAhead, paths)
+	}
+	model, err := FitGasModel(recent)
+	if err != nil {
+		return nil, err
+	}
+
+	last := recent[len(recent)-1]
+	lastUtilization := 0.0
+	if last.GasLimit > 0 {
+		lastUtilization = float64(last.GasUsed) / float64(last.GasLimit)
+	}
+
+	// fees[i][p] is the base fee of block i+1 after last on path p
+	fees := make([][]uint256.Int, blocksAhead)
+	for i := range fees {
+		fees[i] = make([]uint256.Int, paths)
+	}
+	for p := 0; p < paths; p++ {
+		current := last
+		utilization := lastUtilization
+		for i := 0; i < blocksAhead; i++ {
+			baseFee := Calculate(config, current)
+			fees[i][p] = baseFee
+
+			utilization = model.Next(rng, utilization)
+			current = &types.Block{
+				Number:   current.Number + 1,
+				GasLimit: current.GasLimit,
+				GasUsed:  uint64(utilization * float64(current.GasLimit)),
+				BaseFee:  baseFee,
+			}
+		}
+	}
+
+	bands := make([]Band, blocksAhead)
+	for i, samples := range fees {
+		slices.SortFunc(samples, uint256.Int.Cmp)
+		bands[i] = Band{
+			Number: last.Number + uint64(i) + 1,
+			P10:    percentile(samples, 10),
+			P50:    percentile(samples, 50),
+			P90:    percentile(samples, 90),
+		}
+	}
+	return bands, nil
+}
+
+// percentile returns the nearest-rank pth percentile of sorted samples
+func percentile(sorted []uint256.Int, p int) uint256.Int {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
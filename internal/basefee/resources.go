@@ -0,0 +1,28 @@
+package basefee
+
+import (
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+)
+
+// CalculateResourceBaseFees computes the next block's base fee for every resource
+// on a multidimensional chain. Each resource follows the EIP-1559 rule against its
+// own target; MinBaseFee and MaxBaseFee bound the execution base fee only.
+// It returns all zeros on a chain without a ResourceConfig.
+func CalculateResourceBaseFees(config *constants.ChainConfig, parent *types.Block) types.FeeVector {
+	var fees types.FeeVector
+	if config.Resources == nil || !config.IsLondon(parent.Number+1) {
+		return fees
+	}
+
+	for r, params := range config.Resources {
+		// The fork block starts every resource at its initial base fee
+		if !config.IsLondon(parent.Number) {
+			fees[r] = params.InitialBaseFee
+			continue
+		}
+		fees[r] = updateFee(parent.ResourceBaseFees[r], parent.ResourceGasUsed[r], params.Target, params.Target, config.BaseFeeChangeDenominator)
+	}
+	fees[constants.ExecutionResource] = withinBounds(config, fees[constants.ExecutionResource])
+	return fees
+}
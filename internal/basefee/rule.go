@@ -0,0 +1,86 @@
+package basefee
+
+import (
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// BaseFeeRule is a base fee update mechanism. After the fork block every rule
+// keeps the base fee within the chain's MinBaseFee and MaxBaseFee. Rules that keep state between calls
+// advance it once per parent block number, so calling NextBaseFee again for the
+// same parent (as the validator does after the block producer) returns the same
+// value. Stateful rules must be given the blocks of a single chain in order.
+type BaseFeeRule interface {
+	// Name identifies the rule in simulator output
+	Name() string
+
+	// NextBaseFee returns the base fee of the block following parent
+	NextBaseFee(config *constants.ChainConfig, parent *types.Block) uint256.Int
+}
+
+// TimedRule is a rule that also depends on when the new block is produced
+type TimedRule interface {
+	BaseFeeRule
+
+	// NextBaseFeeAt returns the base fee of the block following parent with the given timestamp
+	NextBaseFeeAt(config *constants.ChainConfig, parent *types.Block, timestamp uint64) uint256.Int
+}
+
+// NextBaseFee returns the base fee rule sets for the block following parent with
+// the given timestamp, passing the timestamp on if rule is a TimedRule
+func NextBaseFee(rule BaseFeeRule, config *constants.ChainConfig, parent *types.Block, timestamp uint64) uint256.Int {
+	if timed, ok := rule.(TimedRule); ok {
+		return timed.NextBaseFeeAt(config, parent, timestamp)
+	}
+	return rule.NextBaseFee(config, parent)
+}
+
+// EIP1559Rule is the update rule from the EIP, computed by Calculate
+type EIP1559Rule struct{}
+
+// Name returns "eip1559"
+func (EIP1559Rule) Name() string { return "eip1559" }
+
+// NextBaseFee returns Calculate(config, parent)
+func (EIP1559Rule) NextBaseFee(config *constants.ChainConfig, parent *types.Block) uint256.Int {
+	return Calculate(config, parent)
+}
+
+// DefaultRule is the rule used by the validator
+var DefaultRule BaseFeeRule = EIP1559Rule{}
+
+// Rules constructs a fresh instance of each rule by name, with default parameters
+var Rules = map[string]func() BaseFeeRule{
+	"eip1559":     func() BaseFeeRule { return EIP1559Rule{} },
+	"exponential": func() BaseFeeRule { return ExponentialRule{} },
+	"aimd": func() BaseFeeRule {
+		return &AIMDRule{Increase: uint256.NewInt(constants.InitialBaseFee / 8), DecreaseDenominator: 8}
+	},
+	"pid":            func() BaseFeeRule { return NewPIDRule(0.125, 0.01, 0.05) },
+	"moving-average": func() BaseFeeRule { return NewMovingAverageRule(8) },
+	"time-aware":     func() BaseFeeRule { return NewTimeAwareRule(constants.SecondsPerSlot) },
+}
+
+// withinBounds clamps a base fee to the chain's floor and ceiling
+func withinBounds(config *constants.ChainConfig, fee uint256.Int) uint256.Int {
+	if config.MinBaseFee != nil {
+		fee = uint256.MaxOf(fee, *config.MinBaseFee)
+	}
+	if config.MaxBaseFee != nil {
+		fee = uint256.MinOf(fee, *config.MaxBaseFee)
+	}
+	return fee
+}
+
+// forkBaseFee returns the base fee fixed by the London transition, if any:
+// zero before the fork and the initial base fee on the fork block
+func forkBaseFee(config *constants.ChainConfig, parent *types.Block) (uint256.Int, bool) {
+	switch number := parent.Number + 1; {
+	case !config.IsLondon(number):
+		return uint256.Zero, true
+	case number == config.LondonBlock:
+		return config.InitialBaseFee, true
+	}
+	return uint256.Zero, false
+}
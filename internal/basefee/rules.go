@@ -0,0 +1,226 @@
+package basefee
+
+import (
+	"math/big"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// expScale is the fixed-point scale used to divide by an exponential
+var expScale = uint256.NewInt(1_000_000_000_000_000_000)
+
+// ExponentialRule sets the base fee to an exponential of the cumulative excess gas,
+// as EIP-4844 does for blob gas: each block multiplies the fee by
+// e^((gasUsed - target) / (target * denominator)), so the fee depends only on the
+// total gas used above target, not on the order of the blocks.
+type ExponentialRule struct{}
+
+// Name returns "exponential"
+func (ExponentialRule) Name() string { return "exponential" }
+
+// NextBaseFee returns the fork base fee, or the rule's update within the chain's bounds
+func (r ExponentialRule) NextBaseFee(config *constants.ChainConfig, parent *types.Block) uint256.Int {
+	if fee, ok := forkBaseFee(config, parent); ok {
+		return fee
+	}
+	return withinBounds(config, r.next(config, parent))
+}
+
+// next applies the exponential update to the parent base fee (at least 1 wei, so the fee can recover from zero)
+func (r ExponentialRule) next(config *constants.ChainConfig, parent *types.Block) uint256.Int {
+	baseFee := uint256.MaxOf(parent.BaseFee, uint256.NewInt(1))
+	target := parent.GasTarget(config)
+	fraction, overflow := uint256.NewInt(target).MulOverflow(uint256.NewInt(config.BaseFeeChangeDenominator))
+	if overflow || fraction.IsZero() {
+		return baseFee
+	}
+
+	if parent.GasUsed >= target {
+		return FakeExponential(baseFee, uint256.NewInt(parent.GasUsed-target), fraction)
+	}
+	growth := FakeExponential(expScale, uint256.NewInt(target-parent.GasUsed), fraction)
+	return mulDiv(baseFee, expScale, growth, uint256.NewInt(1))
+}
+
+// AIMDRule is additive-increase/multiplicative-decrease: a block above target
+// raises the fee by Increase, a block below target cuts it by 1/DecreaseDenominator
+type AIMDRule struct {
+	Increase            uint256.Int
+	DecreaseDenominator uint64
+}
+
+// Name returns "aimd"
+func (r *AIMDRule) Name() string { return "aimd" }
+
+// NextBaseFee returns the fork base fee, or the rule's update within the chain's bounds
+func (r *AIMDRule) NextBaseFee(config *constants.ChainConfig, parent *types.Block) uint256.Int {
+	if fee, ok := forkBaseFee(config, parent); ok {
+		return fee
+	}
+	return withinBounds(config, r.next(config, parent))
+}
+
+// next applies the additive or multiplicative step
+func (r *AIMDRule) next(config *constants.ChainConfig, parent *types.Block) uint256.Int {
+	target := parent.GasTarget(config)
+	switch {
+	case parent.GasUsed > target:
+		if fee, overflow := parent.BaseFee.AddOverflow(r.Increase); !overflow {
+			return fee
+		}
+		return uint256.Max
+	case parent.GasUsed < target && r.DecreaseDenominator != 0:
+		return parent.BaseFee.Sub(parent.BaseFee.Div(uint256.NewInt(r.DecreaseDenominator)))
+	}
+	return parent.BaseFee
+}
+
+// PIDRule is a proportional-integral-derivative controller on the relative gas
+// error e = (gasUsed - target) / target. The fee is multiplied by
+// 1 + Kp*e + Ki*sum(e) + Kd*(e - previous e), clamped at zero.
+type PIDRule struct {
+	Kp, Ki, Kd float64
+
+	seen       bool
+	lastNumber uint64 // Parent block number the state below includes
+	lastError  float64
+	integral   float64
+	derivative float64
+}
+
+// NewPIDRule returns a PID rule with the given gains and no accumulated error
+func NewPIDRule(kp, ki, kd float64) *PIDRule {
+	return &PIDRule{Kp: kp, Ki: ki, Kd: kd}
+}
+
+// Name returns "pid"
+func (r *PIDRule) Name() string { return "pid" }
+
+// NextBaseFee returns the fork base fee, or the rule's update within the chain's bounds
+func (r *PIDRule) NextBaseFee(config *constants.ChainConfig, parent *types.Block) uint256.Int {
+	if fee, ok := forkBaseFee(config, parent); ok {
+		return fee
+	}
+	return withinBounds(config, r.next(config, parent))
+}
+
+// next folds the parent into the controller state and scales its base fee (at least 1 wei)
+func (r *PIDRule) next(config *constants.ChainConfig, parent *types.Block) uint256.Int {
+	target := parent.GasTarget(config)
+	if target == 0 {
+		return parent.BaseFee
+	}
+
+	if !r.seen || parent.Number != r.lastNumber {
+		e := (float64(parent.GasUsed) - float64(target)) / float64(target)
+		r.derivative = e - r.lastError
+		if !r.seen {
+			r.derivative = 0
+		}
+		r.integral += e
+		r.lastError = e
+		r.lastNumber = parent.Number
+		r.seen = true
+	}
+
+	factor := 1 + r.Kp*r.lastError + r.Ki*r.integral + r.Kd*r.derivative
+	if factor <= 0 {
+		return uint256.Zero
+	}
+	baseFee := uint256.MaxOf(parent.BaseFee, uint256.NewInt(1))
+	scaled, _ := new(big.Float).Mul(new(big.Float).SetInt(baseFee.ToBig()), big.NewFloat(factor)).Int(nil)
+	result, overflow := uint256.FromBig(scaled)
+	if overflow {
+		return uint256.Max
+	}
+	return result
+}
+
+// MovingAverageRule applies the EIP-1559 formula to the average gas used over the
+// last Window blocks instead of the parent's gas used alone, smoothing out bursts
+type MovingAverageRule struct {
+	Window int
+
+	seen       bool
+	lastNumber uint64
+	gasUsed    []uint64 // Most recent last
+}
+
+// NewMovingAverageRule returns a moving-average rule over window blocks
+func NewMovingAverageRule(window int) *MovingAverageRule {
+	return &MovingAverageRule{Window: max(window, 1)}
+}
+
+// Name returns "moving-average"
+func (r *MovingAverageRule) Name() string { return "moving-average" }
+
+// NextBaseFee records the parent's gas used and applies Calculate to the window average
+func (r *MovingAverageRule) NextBaseFee(config *constants.ChainConfig, parent *types.Block) uint256.Int {
+	if fee, ok := forkBaseFee(config, parent); ok {
+		return fee
+	}
+
+	if !r.seen || parent.Number != r.lastNumber {
+		r.gasUsed = append(r.gasUsed, parent.GasUsed)
+		if len(r.gasUsed) > r.Window {
+			r.gasUsed = r.gasUsed[len(r.gasUsed)-r.Window:]
+		}
+		r.lastNumber = parent.Number
+		r.seen = true
+	}
+
+	var total uint64
+	for _, gasUsed := range r.gasUsed {
+		total += gasUsed
+	}
+	smoothed := *parent
+	smoothed.GasUsed = total / uint64(len(r.gasUsed))
+	return Calculate(config, &smoothed)
+}
+
+// maxGasTargetPercent caps the time-adjusted gas target below the gas limit
+const maxGasTargetPercent = 95
+
+// TimeAwareRule scales the adjustment by the time elapsed since the parent, after
+// EIP-4396: the parent's gas target is multiplied by elapsed / SlotTime (capped at
+// 95% of the gas limit). A block that follows a missed slot comes twice as long
+// after its parent, so the parent's gas used is measured against up to twice the
+// target and the missed slot does not push the fee up as a full block would.
+// The fee depends only on the two headers, so producer and validator agree.
+type TimeAwareRule struct {
+	SlotTime uint64 // Seconds
+}
+
+// NewTimeAwareRule returns a time-aware rule for the given slot time in seconds
+func NewTimeAwareRule(slotTime uint64) *TimeAwareRule {
+	return &TimeAwareRule{SlotTime: max(slotTime, 1)}
+}
+
+// Name returns "time-aware"
+func (r *TimeAwareRule) Name() string { return "time-aware" }
+
+// NextBaseFee returns the base fee of a block one slot after parent
+func (r *TimeAwareRule) NextBaseFee(config *constants.ChainConfig, parent *types.Block) uint256.Int {
+	return r.NextBaseFeeAt(config, parent, parent.Timestamp+r.SlotTime)
+}
+
+// NextBaseFeeAt returns the fork base fee, or the rule's update for a block at
+// timestamp within the chain's bounds. A timestamp not after the parent's counts as one slot.
+func (r *TimeAwareRule) NextBaseFeeAt(config *constants.ChainConfig, parent *types.Block, timestamp uint64) uint256.Int {
+	if fee, ok := forkBaseFee(config, parent); ok {
+		return fee
+	}
+
+	elapsed := r.SlotTime
+	if timestamp > parent.Timestamp {
+		elapsed = timestamp - parent.Timestamp
+	}
+	target := parent.GasTarget(config)
+	adjusted := uint256.MinOf(
+		mulDiv(uint256.NewInt(target), uint256.NewInt(elapsed), uint256.NewInt(r.SlotTime), uint256.NewInt(1)),
+		uint256.NewInt(maxGasTargetPercent*parent.GasLimit/100),
+	)
+	return withinBounds(config, update(config, parent, adjusted.Uint64()))
+}
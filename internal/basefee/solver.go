@@ -0,0 +1,90 @@
+package basefee
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// ErrTargetUnreachable is returned when no gas schedule moves the base fee to the target
+var ErrTargetUnreachable = errors.New("target base fee unreachable")
+
+// Schedule is a gas usage plan for the blocks after a parent
+type Schedule struct {
+	// GasUsed is the gas used by each block after the parent
+	GasUsed []uint64
+
+	// BaseFees are the base fees of the blocks after the parent, one more than
+	// GasUsed: the last entry is the first to reach the target
+	BaseFees []uint256.Int
+}
+
+// Blocks returns the number of blocks whose gas usage must be controlled
+func (s *Schedule) Blocks() int { return len(s.GasUsed) }
+
+// SolveGasSchedule returns the fastest gas schedule that moves the base fee of the
+// blocks after parent to target: at or above it when raising the fee, at or below
+// it when lowering. Every block but the last is full (or empty, going down); the
+// last uses the least (or most) gas that still reaches the target. The gas limit
+// stays at the parent's. Gives up with ErrTargetUnreachable after maxBlocks
+// blocks, or when the fee can no longer move towards the target (an empty block
+// cannot lower a fee below 8 wei).
+func SolveGasSchedule(config *constants.ChainConfig, parent *types.Block, target uint256.Int, maxBlocks int) (*Schedule, error) {
+	current := parent
+	fee := Calculate(config, current)
+	up := fee.Lt(target)
+	schedule := &Schedule{BaseFees: []uint256.Int{fee}}
+
+	reached := func(fee uint256.Int) bool {
+		if up {
+			return !fee.Lt(target)
+		}
+		return !fee.Gt(target)
+	}
+
+	for !reached(fee) {
+		if len(schedule.GasUsed) == maxBlocks {
+			return nil, fmt.Errorf("%w: %s after %d blocks, target %s", ErrTargetUnreachable, fee, maxBlocks, target)
+		}
+
+		block := &types.Block{Number: current.Number + 1, GasLimit: current.GasLimit, BaseFee: fee}
+		feeWith := func(gasUsed uint64) uint256.Int {
+			next := *block
+			next.GasUsed = gasUsed
+			return Calculate(config, &next)
+		}
+
+		// Calculate is non-decreasing in gas used, so the extreme block moves the fee furthest
+		extreme := uint64(0)
+		if up {
+			extreme = block.GasLimit
+		}
+		next := feeWith(extreme)
+		if next == fee {
+			return nil, fmt.Errorf("%w: base fee stuck at %s, target %s", ErrTargetUnreachable, fee, target)
+		}
+
+		gasUsed := extreme
+		if reached(next) {
+			// Last block: the least gas reaching the target going up, the most going down
+			limit := int(block.GasLimit)
+			if up {
+				gasUsed = uint64(sort.Search(limit+1, func(g int) bool { return reached(feeWith(uint64(g))) }))
+			} else {
+				gasUsed = uint64(sort.Search(limit+1, func(g int) bool { return !reached(feeWith(uint64(g))) }) - 1)
+			}
+			next = feeWith(gasUsed)
+		}
+
+		block.GasUsed = gasUsed
+		schedule.GasUsed = append(schedule.GasUsed, gasUsed)
+		schedule.BaseFees = append(schedule.BaseFees, next)
+		current, fee = block, next
+	}
+
+	return schedule, nil
+}
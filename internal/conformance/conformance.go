@@ -0,0 +1,141 @@
+// Package conformance checks the base fee calculator and block validator
+// against recorded chain headers
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/hexutil"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// Sentinel errors for conformance checks
+var (
+	ErrMismatch      = errors.New("base fee does not match recorded header")
+	ErrInvalidBlock  = errors.New("recorded block rejected by validator")
+	ErrBadFixture    = errors.New("malformed fixture")
+	ErrUnknownChain  = errors.New("unknown chain")
+	ErrTooFewHeaders = errors.New("fixture needs at least two headers")
+)
+
+// Header is the part of a recorded header checked by the validator, in
+// eth_getBlockByNumber format. Other fields of an RPC response are ignored.
+// BaseFee is absent before London. Fixtures carry no transaction bodies, so
+// the transaction count is not recorded.
+type Header struct {
+	Number    hexutil.Uint64 `json:"number"`
+	Timestamp hexutil.Uint64 `json:"timestamp"`
+	Miner     types.Address  `json:"miner"`
+	ExtraData hexutil.Bytes  `json:"extraData,omitempty"`
+	GasLimit  hexutil.Uint64 `json:"gasLimit"`
+	GasUsed   hexutil.Uint64 `json:"gasUsed"`
+	BaseFee   *hexutil.U256  `json:"baseFeePerGas,omitempty"`
+}
+
+// Fixture is a run of consecutive headers from one chain, oldest first
+type Fixture struct {
+	Description string   `json:"description"`
+	Chain       string   `json:"chain"` // Key of constants.ChainConfigs
+	Headers     []Header `json:"headers"`
+}
+
+// MismatchError reports the first block whose recorded base fee differs from Calculate
+type MismatchError struct {
+	Number   uint64
+	Expected uint256.Int // Computed by basefee.Calculate
+	Got      uint256.Int // Recorded
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%v: block %d: calculated %s, recorded %s", ErrMismatch, e.Number, e.Expected, e.Got)
+}
+
+func (e *MismatchError) Unwrap() error { return ErrMismatch }
+
+// BlockError reports the first recorded block that validator.ValidateBlock rejects
+type BlockError struct {
+	Number uint64
+	Err    error
+}
+
+func (e *BlockError) Error() string {
+	return fmt.Sprintf("recorded block %d rejected: %v", e.Number, e.Err)
+}
+
+func (e *BlockError) Unwrap() error { return e.Err }
+
+// LoadFixture reads a JSON fixture from path
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrBadFixture, path, err)
+	}
+	return &fixture, nil
+}
+
+// Blocks converts the headers to blocks. Fixtures carry no hashes, and this
+// project's header hash differs from Ethereum's, so each block's ParentHash is
+// set to the hash of the previous block.
+func (f *Fixture) Blocks() []*types.Block {
+	blocks := make([]*types.Block, len(f.Headers))
+	for i, h := range f.Headers {
+		block := &types.Block{
+			Number:    uint64(h.Number),
+			Timestamp: uint64(h.Timestamp),
+			Miner:     h.Miner,
+			Extra:     h.ExtraData,
+			GasLimit:  uint64(h.GasLimit),
+			GasUsed:   uint64(h.GasUsed),
+		}
+		if h.BaseFee != nil {
+			block.BaseFee = uint256.Int(*h.BaseFee)
+		}
+		if i > 0 {
+			block.ParentHash = blocks[i-1].Hash()
+		}
+		blocks[i] = block
+	}
+	return blocks
+}
+
+// Verify runs basefee.Calculate and validator.ValidateBlock over the fixture's
+// headers and returns the first mismatch as a *MismatchError or *BlockError
+func (f *Fixture) Verify() error {
+	config, ok := constants.ChainConfigs[f.Chain]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownChain, f.Chain)
+	}
+	return Verify(config, f.Blocks())
+}
+
+// Verify checks consecutive blocks, oldest first, under config. The headers are
+// recorded history, so they are validated as of the newest one's timestamp.
+func Verify(config *constants.ChainConfig, blocks []*types.Block) error {
+	if len(blocks) < 2 {
+		return ErrTooFewHeaders
+	}
+	now := blocks[len(blocks)-1].Timestamp
+
+	for i := 1; i < len(blocks); i++ {
+		parent, block := blocks[i-1], blocks[i]
+
+		if expected := basefee.Calculate(config, parent); block.BaseFee != expected {
+			return &MismatchError{Number: block.Number, Expected: expected, Got: block.BaseFee}
+		}
+		if err := validator.ValidateBlock(config, block, parent, now); err != nil {
+			return &BlockError{Number: block.Number, Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTxExecutionFailed is wrapped by every TxExecutionError
+var ErrTxExecutionFailed = errors.New("transaction execution failed")
+
+// TxExecutionError reports which transaction of a block failed and why.
+// It unwraps to both ErrTxExecutionFailed and the underlying cause.
+type TxExecutionError struct {
+	Index int
+	Err   error
+}
+
+func (e *TxExecutionError) Error() string {
+	return fmt.Sprintf("%v: tx %d: %v", ErrTxExecutionFailed, e.Index, e.Err)
+}
+
+func (e *TxExecutionError) Unwrap() []error { return []error{ErrTxExecutionFailed, e.Err} }
@@ -1,79 +1,171 @@
 package executor
 
 import (
-	"fmt"
-
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
 	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
 )
 
 // ExecutionResult holds the result of transaction execution
 type ExecutionResult struct {
 	GasUsed       uint64
-	BaseFeeAmount uint64 // Amount burned
-	TipAmount     uint64 // Amount paid to miner
+	BaseFeeAmount uint256.Int // Amount burned
+	BlobFeeAmount uint256.Int // Blob fee burned (EIP-4844)
+	TipAmount     uint256.Int // Amount paid to miner
 	Success       bool
-	Error         error
+
+	// Gas used and amount burned per resource in multidimensional blocks, where
+	// BaseFeeAmount is the execution and calldata burn and BlobFeeAmount the blob burn
+	ResourceGasUsed    types.ResourceVector
+	ResourceFeeAmounts types.FeeVector
+	Error              error
 }
 
+// ExecuteTransaction applies tx to state. A failed transaction leaves state unchanged.
 func ExecuteTransaction(tx *types.Transaction, block *types.Block, state *types.State) *ExecutionResult {
 	result := &ExecutionResult{
 		Success: false,
 	}
 
+	// Undo any partial changes if the transaction fails part way
+	snapshot := state.Snapshot()
+	defer func() {
+		if !result.Success {
+			state.RevertToSnapshot(snapshot)
+		}
+	}()
+
 	// Get accounts
 	sender := state.GetAccount(tx.From)
 	miner := state.GetAccount(block.Miner)
 
 	// Calculate fees
-	effectiveGasPrice := tx.EffectiveGasPrice(block.BaseFee)
-	priorityFee := tx.EffectivePriorityFee(block.BaseFee)
+	effectiveGasPrice, err := tx.EffectiveGasPrice(block.BaseFee)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	priorityFee, err := tx.EffectivePriorityFee(block.BaseFee)
+	if err != nil {
+		result.Error = err
+		return result
+	}
 
-	// Deduct upfront cost (gas + value)
-	// Deduct upfront cost (gas + value) based on MAX fee
-	upfrontGasCost := tx.GasLimit * tx.MaxFeePerGas
-	totalCost := upfrontGasCost + tx.Value
+	// Blob gas is priced by the blob base fee, derived from the block's excess blob gas,
+	// unless the block prices every resource with its own base fee
+	multidimensional := block.IsMultidimensional()
+	blobBaseFee := basefee.CalculateBlobBaseFee(block.ExcessBlobGas)
+	switch {
+	case multidimensional:
+		err = tx.ValidateResourceFees(block.ResourceBaseFees)
+	case tx.Type == types.ResourceFeeTxType:
+		err = types.ErrNotMultidimensional
+	default:
+		err = tx.ValidateBlobFee(blobBaseFee)
+	}
+	if err != nil {
+		result.Error = err
+		return result
+	}
 
-	if err := sender.Deduct(totalCost); err != nil {
-		result.Error = fmt.Errorf("insufficient funds for gas + value: %w", err)
+	// Upfront cost (gas + blob gas + value) based on MAX fee
+	totalCost, err := tx.MaxCost()
+	if err != nil {
+		result.Error = err
 		return result
 	}
 
 	// Execute transaction (simplified - actual execution would call EVM)
-	gasUsed := executeTransaction(tx)
-	result.GasUsed = gasUsed
+	gasUsed, err := executeTransaction(tx)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	// Refuse to charge more gas than the sender paid for, before any state changes
+	if gasUsed > tx.GasLimit {
+		result.Error = &types.IntrinsicGasError{GasLimit: tx.GasLimit, IntrinsicGas: gasUsed}
+		return result
+	}
 
-	// Calculate actual costs
 	// Calculate actual costs
 	// Refund = (GasLimit * MaxFee) - (GasUsed * EffectiveFee)
 	//        = (GasLimit - GasUsed) * MaxFee + GasUsed * (MaxFee - EffectiveFee)
 	// Simplified: Refund unused gas @ MaxFee + Refund overpayment on used gas
+	var refundAmount, tipAmount, baseFeeAmount, blobFeeAmount uint256.Int
+	if multidimensional {
+		used, err := resourceGasUsed(tx, gasUsed)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		var burned types.FeeVector
+		refundAmount, tipAmount, burned, err = settleResources(tx, used, priorityFee, block.ResourceBaseFees)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		if baseFeeAmount, err = types.SafeAdd(burned[constants.ExecutionResource], burned[constants.CalldataResource]); err != nil {
+			result.Error = err
+			return result
+		}
+		blobFeeAmount = burned[constants.BlobResource]
+		result.ResourceGasUsed = used
+		result.ResourceFeeAmounts = burned
+	} else {
+		refundAmount, tipAmount, baseFeeAmount, err = settle(tx, gasUsed, effectiveGasPrice, priorityFee, block.BaseFee)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		blobRefund, burned, err := settleBlobGas(tx, blobBaseFee)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		blobFeeAmount = burned
+		if refundAmount, err = types.SafeAdd(refundAmount, blobRefund); err != nil {
+			result.Error = err
+			return result
+		}
+	}
 
-	remainderGas := tx.GasLimit - gasUsed
-	refundAmount := remainderGas * tx.MaxFeePerGas
-
-	// Add refund for the difference between max fee and effective fee for used gas
-	overpaymentPerGas := tx.MaxFeePerGas - effectiveGasPrice
-	refundAmount += gasUsed * overpaymentPerGas
+	if err := sender.Deduct(totalCost); err != nil {
+		result.Error = err
+		return result
+	}
+	result.GasUsed = gasUsed
 
 	// Refund unused gas to sender
-	sender.Add(refundAmount)
+	if err := sender.Add(refundAmount); err != nil {
+		result.Error = err
+		return result
+	}
 
 	// Transfer value to recipient (if not contract creation)
-	if tx.To != "" {
-		recipient := state.GetAccount(tx.To)
-		recipient.Add(tx.Value)
+	if tx.To != nil {
+		recipient := state.GetAccount(*tx.To)
+		if err := recipient.Add(tx.Value); err != nil {
+			result.Error = err
+			return result
+		}
 	}
 
 	// Pay miner the priority fee (tip)
-	tipAmount := gasUsed * priorityFee
-	miner.Add(tipAmount)
+	if err := miner.Add(tipAmount); err != nil {
+		result.Error = err
+		return result
+	}
 	result.TipAmount = tipAmount
 
 	// Base fee is BURNED (not given to anyone)
-	baseFeeAmount := gasUsed * block.BaseFee
 	result.BaseFeeAmount = baseFeeAmount
 	// Note: baseFeeAmount is effectively burned as it's not added to any account
 
+	// The blob fee is burned as well
+	result.BlobFeeAmount = blobFeeAmount
+
 	// Increment sender nonce
 	sender.IncrementNonce()
 
@@ -81,32 +173,173 @@ func ExecuteTransaction(tx *types.Transaction, block *types.Block, state *types.
 	return result
 }
 
-// executeTransaction simulates transaction execution
-// In a real implementation, this would call the EVM
-func executeTransaction(tx *types.Transaction) uint64 {
-	// Simple simulation: use 21000 gas for transfer, more for contract calls
-	baseGas := uint64(21000)
+// settle computes the refund, miner tip and burned amount for gasUsed using checked arithmetic
+func settle(tx *types.Transaction, gasUsed uint64, effectiveGasPrice, priorityFee, baseFee uint256.Int) (refund, tip, burned uint256.Int, err error) {
+	remainderGas, err := types.SafeSubGas(tx.GasLimit, gasUsed)
+	if err != nil {
+		return refund, tip, burned, err
+	}
+	if refund, err = types.GasCost(remainderGas, tx.GasFeeCap()); err != nil {
+		return refund, tip, burned, err
+	}
 
-	if len(tx.Data) > 0 {
-		// Contract call/creation uses more gas
-		dataGas := uint64(len(tx.Data)) * 16 // 16 gas per byte
-		return baseGas + dataGas
+	// Add refund for the difference between max fee and effective fee for used gas
+	overpaymentPerGas, err := types.SafeSub(tx.GasFeeCap(), effectiveGasPrice)
+	if err != nil {
+		return refund, tip, burned, err
 	}
+	overpayment, err := types.GasCost(gasUsed, overpaymentPerGas)
+	if err != nil {
+		return refund, tip, burned, err
+	}
+	if refund, err = types.SafeAdd(refund, overpayment); err != nil {
+		return refund, tip, burned, err
+	}
+
+	if tip, err = types.GasCost(gasUsed, priorityFee); err != nil {
+		return refund, tip, burned, err
+	}
+	if burned, err = types.GasCost(gasUsed, baseFee); err != nil {
+		return refund, tip, burned, err
+	}
+
+	return refund, tip, burned, nil
+}
+
+// settleBlobGas splits the blob gas prepaid at MaxFeePerBlobGas into the refund and the burned blob fee
+func settleBlobGas(tx *types.Transaction, blobBaseFee uint256.Int) (refund, burned uint256.Int, err error) {
+	blobGas := tx.BlobGas()
+	if blobGas == 0 {
+		return refund, burned, nil
+	}
+
+	overpaymentPerGas, err := types.SafeSub(tx.MaxFeePerBlobGas, blobBaseFee)
+	if err != nil {
+		return refund, burned, err
+	}
+	if refund, err = types.GasCost(blobGas, overpaymentPerGas); err != nil {
+		return refund, burned, err
+	}
+	if burned, err = types.GasCost(blobGas, blobBaseFee); err != nil {
+		return refund, burned, err
+	}
+	return refund, burned, nil
+}
 
-	return baseGas
+// resourceGasUsed splits the gas a transaction used across resources: its calldata
+// gas, the rest as execution gas, and its blob gas
+func resourceGasUsed(tx *types.Transaction, gasUsed uint64) (types.ResourceVector, error) {
+	var used types.ResourceVector
+	calldataGas, err := tx.CalldataGas()
+	if err != nil {
+		return used, err
+	}
+	if used[constants.ExecutionResource], err = types.SafeSubGas(gasUsed, calldataGas); err != nil {
+		return used, err
+	}
+	used[constants.CalldataResource] = calldataGas
+	used[constants.BlobResource] = tx.BlobGas()
+	return used, nil
 }
 
+// settleResources charges each resource at its own base fee and returns the refund of
+// the gas and blob gas prepaid at the fee caps, the miner tip and the amount burned
+// per resource. Only execution gas pays the priority fee, as in EIP-7706.
+func settleResources(tx *types.Transaction, used types.ResourceVector, priorityFee uint256.Int, baseFees types.FeeVector) (refund, tip uint256.Int, burned types.FeeVector, err error) {
+	// Everything paid upfront at the caps, less what is burned and tipped, goes back to the sender
+	maxCost, err := tx.MaxCost()
+	if err != nil {
+		return refund, tip, burned, err
+	}
+	if refund, err = types.SafeSub(maxCost, tx.Value); err != nil {
+		return refund, tip, burned, err
+	}
+
+	for r := range constants.NumResources {
+		if burned[r], err = types.GasCost(used[r], baseFees[r]); err != nil {
+			return refund, tip, burned, err
+		}
+		if refund, err = types.SafeSub(refund, burned[r]); err != nil {
+			return refund, tip, burned, err
+		}
+	}
+	if tip, err = types.GasCost(used[constants.ExecutionResource], priorityFee); err != nil {
+		return refund, tip, burned, err
+	}
+	if refund, err = types.SafeSub(refund, tip); err != nil {
+		return refund, tip, burned, err
+	}
+	return refund, tip, burned, nil
+}
+
+// executeTransaction simulates transaction execution
+// In a real implementation, this would call the EVM
+func executeTransaction(tx *types.Transaction) (uint64, error) {
+	// Simple simulation: a transaction consumes exactly its intrinsic gas
+	// (21000 base, calldata, access list and contract creation costs)
+	return tx.IntrinsicGas()
+}
+
+// ExecuteBlock applies every transaction of the block to state, atomically: if any
+// transaction fails, the changes of the earlier ones are reverted too and the
+// results so far are returned with a *TxExecutionError. On success the changes stay
+// in the journal, so a caller that then rejects the block (for example in
+// validator.ValidateExecutedBlock) can revert to a snapshot taken before the call.
+// The caller calls State.Commit once it accepts the block.
 func ExecuteBlock(block *types.Block, state *types.State) ([]*ExecutionResult, error) {
 	results := make([]*ExecutionResult, 0, len(block.Transactions))
+	snapshot := state.Snapshot()
 
-	for _, tx := range block.Transactions {
+	for i, tx := range block.Transactions {
 		result := ExecuteTransaction(tx, block, state)
 		results = append(results, result)
 
 		if !result.Success {
-			return results, fmt.Errorf("transaction execution failed: %v", result.Error)
+			state.RevertToSnapshot(snapshot)
+			return results, &TxExecutionError{Index: i, Err: result.Error}
 		}
 	}
 
 	return results, nil
 }
+
+// TotalGasUsed returns the gas used by all results, the value a block's GasUsed must hold once executed
+func TotalGasUsed(results []*ExecutionResult) (uint64, error) {
+	var total uint64
+	for _, result := range results {
+		var err error
+		if total, err = types.SafeAddGas(total, result.GasUsed); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// TotalResourceGasUsed returns the gas used per resource by all results, the value a
+// multidimensional block's ResourceGasUsed must hold once executed
+func TotalResourceGasUsed(results []*ExecutionResult) (types.ResourceVector, error) {
+	var total types.ResourceVector
+	for _, result := range results {
+		var err error
+		if total, err = total.Add(result.ResourceGasUsed); err != nil {
+			return types.ResourceVector{}, err
+		}
+	}
+	return total, nil
+}
+
+// SealBlock replaces the gas limits reserved by AddTransaction with the gas the
+// receipts used: GasUsed, and ResourceGasUsed on a multidimensional block
+func SealBlock(block *types.Block, results []*ExecutionResult) error {
+	gasUsed, err := TotalGasUsed(results)
+	if err != nil {
+		return err
+	}
+	if block.IsMultidimensional() {
+		if block.ResourceGasUsed, err = TotalResourceGasUsed(results); err != nil {
+			return err
+		}
+	}
+	block.GasUsed = gasUsed
+	return nil
+}
@@ -0,0 +1,88 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/hexutil"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// resultJSON mirrors the receipt fields of eth_getTransactionReceipt (status, gasUsed)
+// and adds the fee split computed by the executor
+type resultJSON struct {
+	Status        hexutil.Uint64 `json:"status"`
+	GasUsed       hexutil.Uint64 `json:"gasUsed"`
+	BaseFeeAmount hexutil.U256   `json:"baseFeeAmount"`
+	BlobFeeAmount hexutil.U256   `json:"blobFeeAmount"`
+	TipAmount     hexutil.U256   `json:"tipAmount"`
+	Error         string         `json:"error,omitempty"`
+
+	// Per-resource usage and burn, present for multidimensional blocks only
+	ResourceGasUsed    *[constants.NumResources]hexutil.Uint64 `json:"resourceGasUsed,omitempty"`
+	ResourceFeeAmounts *[constants.NumResources]hexutil.U256   `json:"resourceFeeAmounts,omitempty"`
+}
+
+// MarshalJSON encodes the result with hex quantities; status is 0x1 on success and 0x0 on failure
+func (r *ExecutionResult) MarshalJSON() ([]byte, error) {
+	enc := resultJSON{
+		GasUsed:       hexutil.Uint64(r.GasUsed),
+		BaseFeeAmount: hexutil.U256(r.BaseFeeAmount),
+		BlobFeeAmount: hexutil.U256(r.BlobFeeAmount),
+		TipAmount:     hexutil.U256(r.TipAmount),
+	}
+	if r.Success {
+		enc.Status = 1
+	}
+	if r.Error != nil {
+		enc.Error = r.Error.Error()
+	}
+	if r.ResourceGasUsed != (types.ResourceVector{}) {
+		var gasUsed [constants.NumResources]hexutil.Uint64
+		for i, gas := range r.ResourceGasUsed {
+			gasUsed[i] = hexutil.Uint64(gas)
+		}
+		enc.ResourceGasUsed = &gasUsed
+	}
+	if r.ResourceFeeAmounts != (types.FeeVector{}) {
+		var amounts [constants.NumResources]hexutil.U256
+		for i, amount := range r.ResourceFeeAmounts {
+			amounts[i] = hexutil.U256(amount)
+		}
+		enc.ResourceFeeAmounts = &amounts
+	}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON decodes a result produced by MarshalJSON. The error, if any,
+// comes back as a plain message: its type and wrapped sentinels are not preserved.
+func (r *ExecutionResult) UnmarshalJSON(input []byte) error {
+	var dec resultJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+
+	*r = ExecutionResult{
+		GasUsed:       uint64(dec.GasUsed),
+		BaseFeeAmount: uint256.Int(dec.BaseFeeAmount),
+		BlobFeeAmount: uint256.Int(dec.BlobFeeAmount),
+		TipAmount:     uint256.Int(dec.TipAmount),
+		Success:       dec.Status == 1,
+	}
+	if dec.Error != "" {
+		r.Error = errors.New(dec.Error)
+	}
+	if dec.ResourceGasUsed != nil {
+		for i, gas := range dec.ResourceGasUsed {
+			r.ResourceGasUsed[i] = uint64(gas)
+		}
+	}
+	if dec.ResourceFeeAmounts != nil {
+		for i, amount := range dec.ResourceFeeAmounts {
+			r.ResourceFeeAmounts[i] = uint256.Int(amount)
+		}
+	}
+	return nil
+}
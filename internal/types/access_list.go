@@ -0,0 +1,102 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/rlp"
+)
+
+// AccessTuple is an address together with the storage slots a transaction pre-declares (EIP-2930)
+type AccessTuple struct {
+	Address     Address
+	StorageKeys []Hash
+}
+
+// AccessList is the list of addresses and storage keys a transaction plans to access
+type AccessList []AccessTuple
+
+// StorageKeys returns the total number of storage keys across all tuples
+func (al AccessList) StorageKeys() int {
+	n := 0
+	for _, tuple := range al {
+		n += len(tuple.StorageKeys)
+	}
+	return n
+}
+
+// IntrinsicGas returns the gas charged for declaring the list:
+// 2400 per address and 1900 per storage key
+func (al AccessList) IntrinsicGas() (uint64, error) {
+	addressGas, err := SafeMulGas(uint64(len(al)), constants.TxAccessListAddressGas)
+	if err != nil {
+		return 0, err
+	}
+	keyGas, err := SafeMulGas(uint64(al.StorageKeys()), constants.TxAccessListStorageKeyGas)
+	if err != nil {
+		return 0, err
+	}
+	return SafeAddGas(addressGas, keyGas)
+}
+
+// encode returns the RLP encoding [[address, [storageKey, ...]], ...]
+func (al AccessList) encode() []byte {
+	tuples := make([][]byte, len(al))
+	for i, tuple := range al {
+		keys := make([][]byte, len(tuple.StorageKeys))
+		for j, key := range tuple.StorageKeys {
+			keys[j] = rlp.EncodeBytes(key[:])
+		}
+		tuples[i] = rlp.EncodeList(rlp.EncodeBytes(tuple.Address[:]), rlp.EncodeList(keys...))
+	}
+	return rlp.EncodeList(tuples...)
+}
+
+// decodeAccessListInto decodes an RLP access list, keeping nil for an empty list
+func decodeAccessListInto(dst *AccessList) func([]byte) error {
+	return func(b []byte) error {
+		tuples, err := rlp.ListElements(b)
+		if err != nil {
+			return err
+		}
+
+		var al AccessList
+		for i, raw := range tuples {
+			fields, err := rlp.ListElements(raw)
+			if err != nil {
+				return fmt.Errorf("tuple %d: %w", i, err)
+			}
+			if len(fields) != 2 {
+				return fmt.Errorf("tuple %d: expected 2 fields, got %d", i, len(fields))
+			}
+
+			addr, _, err := rlp.SplitString(fields[0])
+			if err != nil {
+				return fmt.Errorf("tuple %d: address: %w", i, err)
+			}
+			if len(addr) != AddressLength {
+				return fmt.Errorf("tuple %d: %w: %d bytes", i, ErrInvalidAddress, len(addr))
+			}
+
+			rawKeys, err := rlp.ListElements(fields[1])
+			if err != nil {
+				return fmt.Errorf("tuple %d: storage keys: %w", i, err)
+			}
+			tuple := AccessTuple{Address: BytesToAddress(addr)}
+			for j, rawKey := range rawKeys {
+				key, _, err := rlp.SplitString(rawKey)
+				if err != nil {
+					return fmt.Errorf("tuple %d: storage key %d: %w", i, j, err)
+				}
+				if len(key) != HashLength {
+					return fmt.Errorf("tuple %d: storage key %d: %w: %d bytes", i, j, ErrInvalidHash, len(key))
+				}
+				tuple.StorageKeys = append(tuple.StorageKeys, BytesToHash(key))
+			}
+			al = append(al, tuple)
+		}
+
+		*dst = al
+		return nil
+	}
+}
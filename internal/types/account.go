@@ -1,15 +1,19 @@
 package types
 
-import "fmt"
+import (
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
 
 // Account represents an Ethereum account
 type Account struct {
-	Address string
+	Address Address
 	Nonce   uint64
-	Balance uint64
+	Balance uint256.Int // Balance in wei
+
+	state *State // State journaling changes to the account, if any
 }
 
-func NewAccount(address string, balance uint64) *Account {
+func NewAccount(address Address, balance uint256.Int) *Account {
 	return &Account{
 		Address: address,
 		Nonce:   0,
@@ -18,58 +22,86 @@ func NewAccount(address string, balance uint64) *Account {
 }
 
 // check if the account has enough balance
-func (a *Account) CanPay(amount uint64) bool {
-	return a.Balance >= amount
+func (a *Account) CanPay(amount uint256.Int) bool {
+	return a.Balance.Cmp(amount) >= 0
 }
 
 // remove the amount from balance
-func (a *Account) Deduct(amount uint64) error {
+func (a *Account) Deduct(amount uint256.Int) error {
 	if !a.CanPay(amount) {
-		return fmt.Errorf("innufficient balance: have %d, need %d", a.Balance, amount)
+		return &InsufficientFundsError{Address: a.Address, Balance: a.Balance, Cost: amount}
 	}
 
-	a.Balance -= amount
+	a.setBalance(a.Balance.Sub(amount))
 	return nil
 }
 
-// add amount to balance
-func (a *Account) Add(amount uint64) {
-	a.Balance += amount
+// add amount to balance, refusing to wrap past 2^256
+func (a *Account) Add(amount uint256.Int) error {
+	balance, err := SafeAdd(a.Balance, amount)
+	if err != nil {
+		return err
+	}
+
+	a.setBalance(balance)
+	return nil
 }
 
 func (a *Account) IncrementNonce() {
+	if a.state != nil {
+		a.state.append(nonceChange{account: a, prev: a.Nonce})
+	}
 	a.Nonce++
 }
 
-// Satate represents teh global state (account)
+// setBalance updates the balance, journaling the old one
+func (a *Account) setBalance(balance uint256.Int) {
+	if a.state != nil {
+		a.state.append(balanceChange{account: a, prev: a.Balance})
+	}
+	a.Balance = balance
+}
+
+// Satate represents teh global state (account). Changes are journaled so
+// they can be rolled back with Snapshot and RevertToSnapshot.
 type State struct {
-	Accounts map[string]*Account
+	accounts map[Address]*Account
+
+	journal []journalEntry
 }
 
 func NewState() *State {
 	return &State{
-		Accounts: make(map[string]*Account),
+		accounts: make(map[Address]*Account),
 	}
 }
 
-func (s *State) GetAccount(address string) *Account {
-	if acc, exists := s.Accounts[address]; exists {
+// Exist reports whether the state holds an account for address, without creating one
+func (s *State) Exist(address Address) bool {
+	_, exists := s.accounts[address]
+	return exists
+}
+
+func (s *State) GetAccount(address Address) *Account {
+	if acc, exists := s.accounts[address]; exists {
 		return acc
 	}
 
-	acc := NewAccount(address, 0)
-	s.Accounts[address] = acc
+	acc := NewAccount(address, uint256.Zero)
+	s.SetAccount(address, acc)
 	return acc
 }
 
-func (s *State) SetAccount(address string, account *Account) {
-	s.Accounts[address] = account
+func (s *State) SetAccount(address Address, account *Account) {
+	s.append(accountChange{address: address, prev: s.accounts[address]})
+	account.state = s
+	s.accounts[address] = account
 }
 
-func (s *State) GetBalance(address string) uint64 {
+func (s *State) GetBalance(address Address) uint256.Int {
 	return s.GetAccount(address).Balance
 }
 
-func (s *State) GetNonce(address string) uint64 {
+func (s *State) GetNonce(address Address) uint64 {
 	return s.GetAccount(address).Nonce
 }
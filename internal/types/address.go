@@ -0,0 +1,119 @@
+package types
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/crypto"
+)
+
+// AddressLength is the size of an account address in bytes
+const AddressLength = 20
+
+var (
+	// ErrInvalidAddress is returned when a string is not 20 hex-encoded bytes
+	ErrInvalidAddress = errors.New("invalid address")
+
+	// ErrAddressChecksum is returned when a mixed-case address fails its EIP-55 checksum
+	ErrAddressChecksum = errors.New("invalid address checksum")
+)
+
+// Address represents a 20-byte account address.
+// The zero value is the all-zero address; a nil *Address in Transaction.To means contract creation.
+type Address [AddressLength]byte
+
+// BytesToAddress returns the address made of the last 20 bytes of b, left-padded with zeros
+func BytesToAddress(b []byte) Address {
+	var a Address
+	if len(b) > AddressLength {
+		b = b[len(b)-AddressLength:]
+	}
+	copy(a[AddressLength-len(b):], b)
+	return a
+}
+
+// HexToAddress converts a hex string to an address, panicking on malformed input.
+// It is intended for constants and tests; use ParseAddress for untrusted input.
+func HexToAddress(s string) Address {
+	a, err := ParseAddress(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// ParseAddress parses a 0x-prefixed (or bare) 40 character hex address.
+// All-lowercase and all-uppercase input is accepted as is; mixed case must carry a valid EIP-55 checksum.
+func ParseAddress(s string) (Address, error) {
+	raw := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(raw) != 2*AddressLength {
+		return Address{}, fmt.Errorf("%w: %q has %d hex characters, want %d", ErrInvalidAddress, s, len(raw), 2*AddressLength)
+	}
+
+	b, err := hex.DecodeString(raw)
+	if err != nil {
+		return Address{}, fmt.Errorf("%w: %q: %v", ErrInvalidAddress, s, err)
+	}
+
+	a := BytesToAddress(b)
+	if raw != strings.ToLower(raw) && raw != strings.ToUpper(raw) && a.Hex()[2:] != raw {
+		return Address{}, fmt.Errorf("%w: %q", ErrAddressChecksum, s)
+	}
+
+	return a, nil
+}
+
+// Bytes returns a copy of the address bytes
+func (a Address) Bytes() []byte {
+	return append([]byte(nil), a[:]...)
+}
+
+// IsZero reports whether a is the all-zero address
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// Hex returns the EIP-55 checksummed hex encoding of a
+func (a Address) Hex() string {
+	lower := hex.EncodeToString(a[:])
+	digest := crypto.Keccak256([]byte(lower))
+
+	out := []byte(lower)
+	for i, c := range out {
+		if c < 'a' {
+			continue // digits are never uppercased
+		}
+		// Uppercase when the matching nibble of the digest is >= 8
+		nibble := digest[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0x0f >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+
+	return "0x" + string(out)
+}
+
+// String implements fmt.Stringer
+func (a Address) String() string {
+	return a.Hex()
+}
+
+// MarshalText implements encoding.TextMarshaler, so addresses encode as checksummed JSON strings and map keys
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.Hex()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (a *Address) UnmarshalText(text []byte) error {
+	parsed, err := ParseAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
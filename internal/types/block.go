@@ -1,22 +1,35 @@
 package types
 
-import "fmt"
+import (
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
 
 // Block represents a block with EIP-1559 base fee
 type Block struct {
 	Number       uint64
-	ParentHash   string
-	Hash         string
+	ParentHash   Hash
 	GasLimit     uint64
 	GasUsed      uint64
-	BaseFee      uint64 // EIP-1559 base fee
+	BaseFee      uint256.Int // EIP-1559 base fee (wei per gas)
 	Transactions []*Transaction
-	Miner        string
+	Miner        Address
 	Timestamp    uint64
+	Extra        []byte // Free-form extra data, at most MaxExtraDataSize bytes
+	TxCount      uint64 // Number of transactions the header commits to
+
+	// EIP-4844 blob gas accounting
+	BlobGasUsed   uint64 // Blob gas consumed by the block's blob transactions
+	ExcessBlobGas uint64 // Running blob gas above target, which sets the blob base fee
+
+	// Multidimensional fee market, on chains with a ResourceConfig. BaseFee
+	// equals ResourceBaseFees[ExecutionResource] there.
+	ResourceGasUsed  ResourceVector
+	ResourceBaseFees FeeVector
 }
 
 // NewBlock creates a new block
-func NewBlock(number uint64, parentHash string, gasLimit uint64, baseFee uint64, miner string) *Block {
+func NewBlock(number uint64, parentHash Hash, gasLimit uint64, baseFee uint256.Int, miner Address) *Block {
 	return &Block{
 		Number:       number,
 		ParentHash:   parentHash,
@@ -27,32 +40,65 @@ func NewBlock(number uint64, parentHash string, gasLimit uint64, baseFee uint64,
 	}
 }
 
-// AddTransaction adds a transaction to the block
+// AddTransaction adds a transaction to the block, reserving its whole gas limit
+// (per resource on multidimensional blocks). Once the block is executed,
+// executor.SealBlock replaces the reservations with the gas of the receipts.
 func (b *Block) AddTransaction(tx *Transaction) error {
 	// Check if adding this tx would exceed gas limit
-	txGas := tx.GasLimit
-	if b.GasUsed+txGas > b.GasLimit {
-		return fmt.Errorf("transaction would exceed block gas limit")
+	gasUsed, err := SafeAddGas(b.GasUsed, tx.GasLimit)
+	if err != nil {
+		return err
+	}
+	if gasUsed > b.GasLimit {
+		return &GasLimitExceededError{GasUsed: gasUsed, GasLimit: b.GasLimit}
+	}
+
+	// Blob gas is metered separately against its own per-block cap
+	blobGasUsed, err := SafeAddGas(b.BlobGasUsed, tx.BlobGas())
+	if err != nil {
+		return err
+	}
+	if blobGasUsed > constants.MaxBlobGasPerBlock {
+		return &BlobGasLimitExceededError{BlobGasUsed: blobGasUsed, MaxBlobGas: constants.MaxBlobGasPerBlock}
+	}
+
+	// Per-resource limits depend on the chain and are checked by the validator
+	resourceGasUsed := b.ResourceGasUsed
+	if b.IsMultidimensional() {
+		txGas, err := tx.ResourceGasLimit()
+		if err != nil {
+			return err
+		}
+		if resourceGasUsed, err = resourceGasUsed.Add(txGas); err != nil {
+			return err
+		}
 	}
 
 	b.Transactions = append(b.Transactions, tx)
-	b.GasUsed += txGas
+	b.TxCount++
+	b.GasUsed = gasUsed
+	b.BlobGasUsed = blobGasUsed
+	b.ResourceGasUsed = resourceGasUsed
 	return nil
 }
 
-// GasTarget returns the target gas usage (50% of limit)
-func (b *Block) GasTarget() uint64 {
-	return b.GasLimit / 2 // ElasticityMultiplier = 2
+// GasTarget returns the target gas usage (the limit divided by the chain's elasticity multiplier).
+// Before London there is no elasticity, so the whole limit is the target.
+func (b *Block) GasTarget(config *constants.ChainConfig) uint64 {
+	if !config.IsLondon(b.Number) {
+		return b.GasLimit
+	}
+	return b.GasLimit / config.ElasticityMultiplier
 }
 
 // IsAboveTarget returns true if block used more than target gas
-func (b *Block) IsAboveTarget() bool {
-	return b.GasUsed > b.GasTarget()
+func (b *Block) IsAboveTarget(config *constants.ChainConfig) bool {
+	return b.GasUsed > b.GasTarget(config)
 }
 
 // IsBelowTarget returns true if block used less than target gas
-func (b *Block) IsBelowTarget() bool {
-	return b.GasUsed < b.GasTarget()
+func (b *Block) IsBelowTarget(config *constants.ChainConfig) bool {
+	return b.GasUsed < b.GasTarget(config)
 }
 
 // Utilization returns gas utilization percentage (0-100)
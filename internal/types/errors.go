@@ -0,0 +1,204 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// Sentinel errors for transaction and account checks. The typed errors below
+// wrap them, so callers can match with errors.Is and read the offending values
+// with errors.As.
+var (
+	ErrNonceTooLow        = errors.New("nonce too low")
+	ErrNonceTooHigh       = errors.New("nonce too high")
+	ErrInsufficientFunds  = errors.New("insufficient funds")
+	ErrFeeCapBelowBaseFee = errors.New("max fee per gas less than base fee")
+	ErrTipAboveFeeCap     = errors.New("max fee per gas less than max priority fee per gas")
+	ErrZeroGasLimit       = errors.New("gas limit cannot be zero")
+	ErrGasLimitExceeded   = errors.New("gas limit exceeded")
+	ErrIntrinsicGas       = errors.New("intrinsic gas too low")
+	ErrMaxInitCodeSize    = errors.New("max initcode size exceeded")
+	ErrInvalidSig         = errors.New("invalid transaction signature")
+	ErrSenderMismatch     = errors.New("recovered sender does not match from address")
+	ErrInvalidChainID     = errors.New("invalid chain ID")
+
+	ErrBlobFeeCapTooLow     = errors.New("max fee per blob gas less than blob base fee")
+	ErrBlobGasLimitExceeded = errors.New("blob gas limit exceeded")
+	ErrBlobTxCreate         = errors.New("blob transaction cannot create a contract")
+	ErrMissingBlobHashes    = errors.New("blob transaction without blob hashes")
+	ErrBlobHashVersion      = errors.New("blob hash has unsupported version")
+
+	ErrResourceFeeCapTooLow = errors.New("max fee less than resource base fee")
+	ErrNotMultidimensional  = errors.New("per-resource fee caps need a multidimensional block")
+
+	ErrMalformedBlock = errors.New("malformed block")
+)
+
+// NonceError reports a transaction nonce that differs from the sender's account nonce
+type NonceError struct {
+	Address    Address
+	TxNonce    uint64
+	StateNonce uint64
+}
+
+func (e *NonceError) Error() string {
+	return fmt.Sprintf("invalid nonce: %v: address %s, have %d, expected %d",
+		e.Unwrap(), e.Address, e.TxNonce, e.StateNonce)
+}
+
+// Unwrap returns ErrNonceTooLow or ErrNonceTooHigh
+func (e *NonceError) Unwrap() error {
+	if e.TxNonce < e.StateNonce {
+		return ErrNonceTooLow
+	}
+	return ErrNonceTooHigh
+}
+
+// InsufficientFundsError reports an account that cannot cover a cost
+type InsufficientFundsError struct {
+	Address Address
+	Balance uint256.Int
+	Cost    uint256.Int
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("%v: address %s, have %s, need %s", ErrInsufficientFunds, e.Address, e.Balance, e.Cost)
+}
+
+func (e *InsufficientFundsError) Unwrap() error { return ErrInsufficientFunds }
+
+// FeeCapError reports a max fee per gas that cannot cover the base fee
+type FeeCapError struct {
+	MaxFeePerGas uint256.Int
+	BaseFee      uint256.Int
+}
+
+func (e *FeeCapError) Error() string {
+	return fmt.Sprintf("%v: max fee per gas %s, base fee %s", ErrFeeCapBelowBaseFee, e.MaxFeePerGas, e.BaseFee)
+}
+
+func (e *FeeCapError) Unwrap() error { return ErrFeeCapBelowBaseFee }
+
+// TipAboveFeeCapError reports a max priority fee per gas above the max fee per gas
+type TipAboveFeeCapError struct {
+	MaxPriorityFeePerGas uint256.Int
+	MaxFeePerGas         uint256.Int
+}
+
+func (e *TipAboveFeeCapError) Error() string {
+	return fmt.Sprintf("%v: max fee per gas %s, max priority fee per gas %s",
+		ErrTipAboveFeeCap, e.MaxFeePerGas, e.MaxPriorityFeePerGas)
+}
+
+func (e *TipAboveFeeCapError) Unwrap() error { return ErrTipAboveFeeCap }
+
+// GasLimitExceededError reports gas that does not fit within a gas limit
+type GasLimitExceededError struct {
+	GasUsed  uint64
+	GasLimit uint64
+}
+
+func (e *GasLimitExceededError) Error() string {
+	return fmt.Sprintf("%v: gas used (%d) exceeds gas limit (%d)", ErrGasLimitExceeded, e.GasUsed, e.GasLimit)
+}
+
+func (e *GasLimitExceededError) Unwrap() error { return ErrGasLimitExceeded }
+
+// IntrinsicGasError reports a gas limit that cannot cover the transaction's intrinsic gas
+type IntrinsicGasError struct {
+	GasLimit     uint64
+	IntrinsicGas uint64
+}
+
+func (e *IntrinsicGasError) Error() string {
+	return fmt.Sprintf("%v: gas limit %d, intrinsic gas %d", ErrIntrinsicGas, e.GasLimit, e.IntrinsicGas)
+}
+
+func (e *IntrinsicGasError) Unwrap() error { return ErrIntrinsicGas }
+
+// ChainIDError reports a transaction signed for a different chain than the one validating it
+type ChainIDError struct {
+	Expected uint64
+	Got      uint64
+}
+
+func (e *ChainIDError) Error() string {
+	return fmt.Sprintf("%v: expected %d, got %d", ErrInvalidChainID, e.Expected, e.Got)
+}
+
+func (e *ChainIDError) Unwrap() error { return ErrInvalidChainID }
+
+// InitCodeSizeError reports contract creation code larger than MaxInitCodeSize
+type InitCodeSizeError struct {
+	Size int
+	Max  int
+}
+
+func (e *InitCodeSizeError) Error() string {
+	return fmt.Sprintf("%v: code size %d, limit %d", ErrMaxInitCodeSize, e.Size, e.Max)
+}
+
+func (e *InitCodeSizeError) Unwrap() error { return ErrMaxInitCodeSize }
+
+// InvalidSignatureError reports signature values that cannot yield a sender
+type InvalidSignatureError struct {
+	Reason string
+}
+
+func (e *InvalidSignatureError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrInvalidSig, e.Reason)
+}
+
+func (e *InvalidSignatureError) Unwrap() error { return ErrInvalidSig }
+
+// SenderMismatchError reports a transaction whose signature recovers to a different account than From
+type SenderMismatchError struct {
+	From      Address
+	Recovered Address
+}
+
+func (e *SenderMismatchError) Error() string {
+	return fmt.Sprintf("%v: from %s, recovered %s", ErrSenderMismatch, e.From, e.Recovered)
+}
+
+func (e *SenderMismatchError) Unwrap() error { return ErrSenderMismatch }
+
+// BlobFeeCapError reports a max fee per blob gas that cannot cover the blob base fee
+type BlobFeeCapError struct {
+	MaxFeePerBlobGas uint256.Int
+	BlobBaseFee      uint256.Int
+}
+
+func (e *BlobFeeCapError) Error() string {
+	return fmt.Sprintf("%v: max fee per blob gas %s, blob base fee %s", ErrBlobFeeCapTooLow, e.MaxFeePerBlobGas, e.BlobBaseFee)
+}
+
+func (e *BlobFeeCapError) Unwrap() error { return ErrBlobFeeCapTooLow }
+
+// BlobGasLimitExceededError reports blob gas above the per-block maximum
+type BlobGasLimitExceededError struct {
+	BlobGasUsed uint64
+	MaxBlobGas  uint64
+}
+
+func (e *BlobGasLimitExceededError) Error() string {
+	return fmt.Sprintf("%v: blob gas used (%d) exceeds maximum (%d)", ErrBlobGasLimitExceeded, e.BlobGasUsed, e.MaxBlobGas)
+}
+
+func (e *BlobGasLimitExceededError) Unwrap() error { return ErrBlobGasLimitExceeded }
+
+// ResourceFeeCapError reports a per-resource fee cap that cannot cover that resource's base fee
+type ResourceFeeCapError struct {
+	Resource constants.Resource
+	MaxFee   uint256.Int
+	BaseFee  uint256.Int
+}
+
+func (e *ResourceFeeCapError) Error() string {
+	return fmt.Sprintf("%v: %s max fee %s, base fee %s", ErrResourceFeeCapTooLow, e.Resource, e.MaxFee, e.BaseFee)
+}
+
+func (e *ResourceFeeCapError) Unwrap() error { return ErrResourceFeeCapTooLow }
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// HashLength is the size of a Keccak-256 hash in bytes
+const HashLength = 32
+
+// ErrInvalidHash is returned when a string is not 32 hex-encoded bytes
+var ErrInvalidHash = errors.New("invalid hash")
+
+// Hash represents a 32-byte Keccak-256 hash
+type Hash [HashLength]byte
+
+// BytesToHash returns the hash made of the last 32 bytes of b, left-padded with zeros
+func BytesToHash(b []byte) Hash {
+	var h Hash
+	if len(b) > HashLength {
+		b = b[len(b)-HashLength:]
+	}
+	copy(h[HashLength-len(b):], b)
+	return h
+}
+
+// HexToHash converts a hex string to a hash, panicking on malformed input.
+// It is intended for constants and tests; use ParseHash for untrusted input.
+func HexToHash(s string) Hash {
+	h, err := ParseHash(s)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// ParseHash parses a 0x-prefixed (or bare) 64 character hex hash
+func ParseHash(s string) (Hash, error) {
+	raw := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(raw) != 2*HashLength {
+		return Hash{}, fmt.Errorf("%w: %q has %d hex characters, want %d", ErrInvalidHash, s, len(raw), 2*HashLength)
+	}
+
+	b, err := hex.DecodeString(raw)
+	if err != nil {
+		return Hash{}, fmt.Errorf("%w: %q: %v", ErrInvalidHash, s, err)
+	}
+	return BytesToHash(b), nil
+}
+
+// Bytes returns a copy of the hash bytes
+func (h Hash) Bytes() []byte {
+	return append([]byte(nil), h[:]...)
+}
+
+// IsZero reports whether h is the all-zero hash
+func (h Hash) IsZero() bool {
+	return h == Hash{}
+}
+
+// Hex returns the 0x-prefixed lowercase hex encoding of h
+func (h Hash) Hex() string {
+	return "0x" + hex.EncodeToString(h[:])
+}
+
+// String implements fmt.Stringer
+func (h Hash) String() string {
+	return h.Hex()
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.Hex()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (h *Hash) UnmarshalText(text []byte) error {
+	parsed, err := ParseHash(string(text))
+	if err != nil {
+		return err
+	}
+	*h = parsed
+	return nil
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/crypto"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/rlp"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// EncodeHeader returns the RLP encoding of the block header fields:
+//
+//	[parentHash, miner, number, gasLimit, gasUsed, timestamp, extraData, baseFeePerGas, blobGasUsed, excessBlobGas, txCount]
+//
+// Multidimensional blocks append [resourceGasUsed, resourceBaseFees], each a list with one entry per resource.
+//
+// This is the subset of the Ethereum header modelled by this project, so the
+// resulting hash is not interchangeable with a mainnet block hash.
+func (b *Block) EncodeHeader() []byte {
+	fields := [][]byte{
+		rlp.EncodeBytes(b.ParentHash[:]),
+		rlp.EncodeBytes(b.Miner[:]),
+		rlp.EncodeUint64(b.Number),
+		rlp.EncodeUint64(b.GasLimit),
+		rlp.EncodeUint64(b.GasUsed),
+		rlp.EncodeUint64(b.Timestamp),
+		rlp.EncodeBytes(b.Extra),
+		encodeUint256(b.BaseFee),
+		rlp.EncodeUint64(b.BlobGasUsed),
+		rlp.EncodeUint64(b.ExcessBlobGas),
+		rlp.EncodeUint64(b.TxCount),
+	}
+	if b.IsMultidimensional() {
+		var gasUsed, baseFees [constants.NumResources][]byte
+		for r := range constants.NumResources {
+			gasUsed[r] = rlp.EncodeUint64(b.ResourceGasUsed[r])
+			baseFees[r] = encodeUint256(b.ResourceBaseFees[r])
+		}
+		fields = append(fields, rlp.EncodeList(gasUsed[:]...), rlp.EncodeList(baseFees[:]...))
+	}
+	return rlp.EncodeList(fields...)
+}
+
+// Hash returns the Keccak-256 hash of the RLP-encoded header
+func (b *Block) Hash() Hash {
+	return BytesToHash(crypto.Keccak256(b.EncodeHeader()))
+}
+
+// encodeUint256 encodes x as an RLP integer
+func encodeUint256(x uint256.Int) []byte {
+	return rlp.EncodeBytes(x.Bytes())
+}
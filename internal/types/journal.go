@@ -0,0 +1,72 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// journalEntry is a state change that can be undone
+type journalEntry interface {
+	revert(s *State)
+}
+
+// accountChange records an account being added to or replaced in the state; prev is nil for a new account
+type accountChange struct {
+	address Address
+	prev    *Account
+}
+
+func (c accountChange) revert(s *State) {
+	if c.prev == nil {
+		delete(s.accounts, c.address)
+		return
+	}
+	s.accounts[c.address] = c.prev
+}
+
+// balanceChange records an account balance before a change
+type balanceChange struct {
+	account *Account
+	prev    uint256.Int
+}
+
+func (c balanceChange) revert(*State) { c.account.Balance = c.prev }
+
+// nonceChange records an account nonce before a change
+type nonceChange struct {
+	account *Account
+	prev    uint64
+}
+
+func (c nonceChange) revert(*State) { c.account.Nonce = c.prev }
+
+// Snapshot returns an identifier for the current state, to pass to RevertToSnapshot.
+// Changes made through State and Account methods are journaled; direct writes to
+// Account fields are not.
+func (s *State) Snapshot() int {
+	return len(s.journal)
+}
+
+// RevertToSnapshot undoes every change made since Snapshot returned id. Snapshots
+// taken after id become invalid. It panics if id is not a valid snapshot.
+func (s *State) RevertToSnapshot(id int) {
+	if id < 0 || id > len(s.journal) {
+		panic(fmt.Sprintf("state snapshot %d cannot be reverted (journal has %d changes)", id, len(s.journal)))
+	}
+	for i := len(s.journal) - 1; i >= id; i-- {
+		s.journal[i].revert(s)
+	}
+	s.journal = s.journal[:id]
+}
+
+// Commit makes every change so far final by discarding the journal, so it does not
+// grow without bound. All earlier snapshots become invalid.
+func (s *State) Commit() {
+	s.journal = nil
+}
+
+// append records a change in the state's journal
+func (s *State) append(entry journalEntry) {
+	s.journal = append(s.journal, entry)
+}
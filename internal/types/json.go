@@ -0,0 +1,398 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/hexutil"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// JSON encodings follow the Ethereum JSON-RPC API (eth_getBlockByNumber,
+// eth_getTransactionByHash): quantities are hex strings and field names match
+// the RPC spelling, so node output can be loaded directly.
+
+// txJSON is the JSON-RPC transaction object. Pointer fields are optional.
+type txJSON struct {
+	Type                 *hexutil.Uint64                       `json:"type"`
+	ChainID              *hexutil.Uint64                       `json:"chainId,omitempty"`
+	Nonce                *hexutil.Uint64                       `json:"nonce"`
+	GasPrice             *hexutil.U256                         `json:"gasPrice,omitempty"`
+	MaxPriorityFeePerGas *hexutil.U256                         `json:"maxPriorityFeePerGas,omitempty"`
+	MaxFeePerGas         *hexutil.U256                         `json:"maxFeePerGas,omitempty"`
+	MaxFeePerBlobGas     *hexutil.U256                         `json:"maxFeePerBlobGas,omitempty"`
+	Gas                  *hexutil.Uint64                       `json:"gas"`
+	To                   *Address                              `json:"to"`
+	Value                *hexutil.U256                         `json:"value"`
+	Input                *hexutil.Bytes                        `json:"input"`
+	AccessList           *AccessList                           `json:"accessList,omitempty"`
+	BlobVersionedHashes  []Hash                                `json:"blobVersionedHashes,omitempty"`
+	MaxFeesPerGas        *[constants.NumResources]hexutil.U256 `json:"maxFeesPerGas,omitempty"`
+	From                 *Address                              `json:"from,omitempty"`
+	V                    *hexutil.U256                         `json:"v"`
+	R                    *hexutil.U256                         `json:"r"`
+	S                    *hexutil.U256                         `json:"s"`
+	YParity              *hexutil.Uint64                       `json:"yParity,omitempty"`
+	Hash                 *Hash                                 `json:"hash,omitempty"`
+}
+
+// MarshalJSON encodes the transaction as a JSON-RPC transaction object
+func (tx *Transaction) MarshalJSON() ([]byte, error) {
+	if !tx.isKnownType() {
+		return nil, fmt.Errorf("%w: 0x%02x", ErrTxTypeNotSupported, tx.Type)
+	}
+
+	hash := tx.Hash()
+	enc := txJSON{
+		Type:  u64JSON(uint64(tx.Type)),
+		Nonce: u64JSON(tx.Nonce),
+		Gas:   u64JSON(tx.GasLimit),
+		To:    tx.To,
+		Value: u256JSON(tx.Value),
+		Input: (*hexutil.Bytes)(&tx.Data),
+		V:     u256JSON(tx.V),
+		R:     u256JSON(tx.R),
+		S:     u256JSON(tx.S),
+		Hash:  &hash,
+	}
+	if tx.Data == nil {
+		enc.Input = &hexutil.Bytes{}
+	}
+	if tx.Type != LegacyTxType || tx.ChainID != 0 {
+		enc.ChainID = u64JSON(tx.ChainID)
+	}
+	if !tx.From.IsZero() {
+		enc.From = &tx.From
+	}
+
+	switch tx.Type {
+	case LegacyTxType, AccessListTxType:
+		enc.GasPrice = u256JSON(tx.GasPrice)
+	case DynamicFeeTxType, BlobTxType:
+		enc.MaxPriorityFeePerGas = u256JSON(tx.MaxPriorityFeePerGas)
+		enc.MaxFeePerGas = u256JSON(tx.MaxFeePerGas)
+	case ResourceFeeTxType:
+		var caps [constants.NumResources]hexutil.U256
+		for r, fee := range tx.ResourceFeeCaps {
+			caps[r] = hexutil.U256(fee)
+		}
+		enc.MaxPriorityFeePerGas = u256JSON(tx.MaxPriorityFeePerGas)
+		enc.MaxFeesPerGas = &caps
+	}
+	if tx.Type != LegacyTxType {
+		al := tx.AccessList
+		if al == nil {
+			al = AccessList{}
+		}
+		enc.AccessList = &al
+		enc.YParity = u64JSON(tx.V.Uint64())
+	}
+	if tx.Type == BlobTxType {
+		enc.MaxFeePerBlobGas = u256JSON(tx.MaxFeePerBlobGas)
+	}
+	if tx.Type == BlobTxType || tx.Type == ResourceFeeTxType {
+		enc.BlobVersionedHashes = tx.BlobHashes
+		if enc.BlobVersionedHashes == nil {
+			enc.BlobVersionedHashes = []Hash{}
+		}
+	}
+
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON decodes a JSON-RPC transaction object. The fee fields required
+// depend on the type; a gasPrice on typed 1559 transactions (the effective price
+// reported by nodes for mined transactions) is ignored. The hash is not checked.
+func (tx *Transaction) UnmarshalJSON(input []byte) error {
+	var dec txJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+
+	var t Transaction
+	if dec.Type == nil {
+		return missingTxField("type")
+	}
+	if uint64(*dec.Type) > 0xff {
+		return fmt.Errorf("%w: 0x%x", ErrTxTypeNotSupported, uint64(*dec.Type))
+	}
+	t.Type = byte(*dec.Type)
+	if !t.isKnownType() {
+		return fmt.Errorf("%w: 0x%02x", ErrTxTypeNotSupported, t.Type)
+	}
+
+	required := []struct {
+		name string
+		set  bool
+	}{
+		{"nonce", dec.Nonce != nil},
+		{"gas", dec.Gas != nil},
+		{"value", dec.Value != nil},
+		{"input", dec.Input != nil},
+		{"v", dec.V != nil},
+		{"r", dec.R != nil},
+		{"s", dec.S != nil},
+	}
+	for _, field := range required {
+		if !field.set {
+			return missingTxField(field.name)
+		}
+	}
+	t.Nonce = uint64(*dec.Nonce)
+	t.GasLimit = uint64(*dec.Gas)
+	t.To = dec.To
+	t.Value = uint256.Int(*dec.Value)
+	if len(*dec.Input) > 0 {
+		t.Data = *dec.Input
+	}
+	t.V, t.R, t.S = uint256.Int(*dec.V), uint256.Int(*dec.R), uint256.Int(*dec.S)
+	if dec.From != nil {
+		t.From = *dec.From
+	}
+
+	switch t.Type {
+	case LegacyTxType, AccessListTxType:
+		if dec.GasPrice == nil {
+			return missingTxField("gasPrice")
+		}
+		t.GasPrice = uint256.Int(*dec.GasPrice)
+	case ResourceFeeTxType:
+		if dec.MaxPriorityFeePerGas == nil {
+			return missingTxField("maxPriorityFeePerGas")
+		}
+		if dec.MaxFeesPerGas == nil {
+			return missingTxField("maxFeesPerGas")
+		}
+		t.MaxPriorityFeePerGas = uint256.Int(*dec.MaxPriorityFeePerGas)
+		for r, fee := range dec.MaxFeesPerGas {
+			t.ResourceFeeCaps[r] = uint256.Int(fee)
+		}
+		t.BlobHashes = dec.BlobVersionedHashes
+	default:
+		if dec.MaxPriorityFeePerGas == nil {
+			return missingTxField("maxPriorityFeePerGas")
+		}
+		if dec.MaxFeePerGas == nil {
+			return missingTxField("maxFeePerGas")
+		}
+		t.MaxPriorityFeePerGas = uint256.Int(*dec.MaxPriorityFeePerGas)
+		t.MaxFeePerGas = uint256.Int(*dec.MaxFeePerGas)
+	}
+
+	switch {
+	case dec.ChainID != nil:
+		t.ChainID = uint64(*dec.ChainID)
+	case t.Type != LegacyTxType:
+		return missingTxField("chainId")
+	case !t.V.IsZero():
+		chainID, err := legacyChainID(t.V)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrMalformedTransaction, err)
+		}
+		t.ChainID = chainID
+	}
+
+	if t.Type != LegacyTxType && dec.AccessList != nil && len(*dec.AccessList) > 0 {
+		t.AccessList = *dec.AccessList
+	}
+
+	if t.Type == BlobTxType {
+		if dec.MaxFeePerBlobGas == nil {
+			return missingTxField("maxFeePerBlobGas")
+		}
+		if dec.BlobVersionedHashes == nil {
+			return missingTxField("blobVersionedHashes")
+		}
+		t.MaxFeePerBlobGas = uint256.Int(*dec.MaxFeePerBlobGas)
+		t.BlobHashes = dec.BlobVersionedHashes
+	}
+
+	*tx = t
+	return nil
+}
+
+// blockJSON is the JSON-RPC block object, restricted to the header fields this project models
+type blockJSON struct {
+	Number        *hexutil.Uint64   `json:"number"`
+	Hash          *Hash             `json:"hash,omitempty"`
+	ParentHash    *Hash             `json:"parentHash"`
+	Miner         *Address          `json:"miner"`
+	GasLimit      *hexutil.Uint64   `json:"gasLimit"`
+	GasUsed       *hexutil.Uint64   `json:"gasUsed"`
+	Timestamp     *hexutil.Uint64   `json:"timestamp"`
+	ExtraData     hexutil.Bytes     `json:"extraData"`
+	BaseFee       *hexutil.U256     `json:"baseFeePerGas,omitempty"`
+	BlobGasUsed   *hexutil.Uint64   `json:"blobGasUsed,omitempty"`
+	ExcessBlobGas *hexutil.Uint64   `json:"excessBlobGas,omitempty"`
+	Transactions  []json.RawMessage `json:"transactions"`
+
+	// Per-resource usage and base fees, present for multidimensional blocks only
+	ResourceGasUsed  *[constants.NumResources]hexutil.Uint64 `json:"resourceGasUsed,omitempty"`
+	ResourceBaseFees *[constants.NumResources]hexutil.U256   `json:"resourceBaseFeesPerGas,omitempty"`
+}
+
+// MarshalJSON encodes the block as a JSON-RPC block object with full transactions.
+// The hash is this project's header hash (see EncodeHeader), not a mainnet block hash.
+// As on a node, baseFeePerGas is left out before London (a zero base fee). Blocks
+// carry no fork schedule, so the blob gas fields are emitted whenever either is
+// non-zero, and left out otherwise, even after Cancun. An omitted field decodes
+// as zero, so the round trip is lossless.
+func (b *Block) MarshalJSON() ([]byte, error) {
+	hash := b.Hash()
+	enc := blockJSON{
+		Number:       u64JSON(b.Number),
+		Hash:         &hash,
+		ParentHash:   &b.ParentHash,
+		Miner:        &b.Miner,
+		GasLimit:     u64JSON(b.GasLimit),
+		GasUsed:      u64JSON(b.GasUsed),
+		Timestamp:    u64JSON(b.Timestamp),
+		ExtraData:    b.Extra,
+		Transactions: make([]json.RawMessage, len(b.Transactions)),
+	}
+	if !b.BaseFee.IsZero() {
+		enc.BaseFee = u256JSON(b.BaseFee)
+	}
+	if b.BlobGasUsed != 0 || b.ExcessBlobGas != 0 {
+		enc.BlobGasUsed = u64JSON(b.BlobGasUsed)
+		enc.ExcessBlobGas = u64JSON(b.ExcessBlobGas)
+	}
+	if b.IsMultidimensional() {
+		var gasUsed [constants.NumResources]hexutil.Uint64
+		var baseFees [constants.NumResources]hexutil.U256
+		for r := range constants.NumResources {
+			gasUsed[r] = hexutil.Uint64(b.ResourceGasUsed[r])
+			baseFees[r] = hexutil.U256(b.ResourceBaseFees[r])
+		}
+		enc.ResourceGasUsed, enc.ResourceBaseFees = &gasUsed, &baseFees
+	}
+	for i, tx := range b.Transactions {
+		raw, err := json.Marshal(tx)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		enc.Transactions[i] = raw
+	}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON decodes a JSON-RPC block object such as an eth_getBlockByNumber response.
+// Fields outside the modelled header are ignored, as is the hash. Transactions
+// listed only by hash carry no body and are skipped, but still count towards
+// TxCount; request full transactions to keep them.
+func (b *Block) UnmarshalJSON(input []byte) error {
+	var dec blockJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+
+	required := []struct {
+		name string
+		set  bool
+	}{
+		{"number", dec.Number != nil},
+		{"parentHash", dec.ParentHash != nil},
+		{"gasLimit", dec.GasLimit != nil},
+		{"gasUsed", dec.GasUsed != nil},
+		{"timestamp", dec.Timestamp != nil},
+	}
+	for _, field := range required {
+		if !field.set {
+			return fmt.Errorf("%w: missing required field %q", ErrMalformedBlock, field.name)
+		}
+	}
+
+	block := Block{
+		Number:       uint64(*dec.Number),
+		ParentHash:   *dec.ParentHash,
+		GasLimit:     uint64(*dec.GasLimit),
+		GasUsed:      uint64(*dec.GasUsed),
+		Timestamp:    uint64(*dec.Timestamp),
+		Extra:        dec.ExtraData,
+		TxCount:      uint64(len(dec.Transactions)),
+		Transactions: make([]*Transaction, 0, len(dec.Transactions)),
+	}
+	if dec.Miner != nil {
+		block.Miner = *dec.Miner
+	}
+	if dec.BaseFee != nil {
+		block.BaseFee = uint256.Int(*dec.BaseFee)
+	}
+	if dec.BlobGasUsed != nil {
+		block.BlobGasUsed = uint64(*dec.BlobGasUsed)
+	}
+	if dec.ExcessBlobGas != nil {
+		block.ExcessBlobGas = uint64(*dec.ExcessBlobGas)
+	}
+	if dec.ResourceGasUsed != nil {
+		for r, gas := range dec.ResourceGasUsed {
+			block.ResourceGasUsed[r] = uint64(gas)
+		}
+	}
+	if dec.ResourceBaseFees != nil {
+		for r, fee := range dec.ResourceBaseFees {
+			block.ResourceBaseFees[r] = uint256.Int(fee)
+		}
+	}
+
+	for i, raw := range dec.Transactions {
+		if len(raw) > 0 && raw[0] == '"' {
+			continue // hash only
+		}
+		tx := new(Transaction)
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return fmt.Errorf("%w: transaction %d: %w", ErrMalformedBlock, i, err)
+		}
+		block.Transactions = append(block.Transactions, tx)
+	}
+
+	*b = block
+	return nil
+}
+
+// MarshalJSON keeps storageKeys an array for tuples without keys, as nodes emit it
+func (t AccessTuple) MarshalJSON() ([]byte, error) {
+	keys := t.StorageKeys
+	if keys == nil {
+		keys = []Hash{}
+	}
+	return json.Marshal(struct {
+		Address     Address `json:"address"`
+		StorageKeys []Hash  `json:"storageKeys"`
+	}{t.Address, keys})
+}
+
+// UnmarshalJSON decodes a JSON-RPC access list entry
+func (t *AccessTuple) UnmarshalJSON(input []byte) error {
+	var dec struct {
+		Address     *Address `json:"address"`
+		StorageKeys []Hash   `json:"storageKeys"`
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Address == nil {
+		return fmt.Errorf("%w: access list entry missing address", ErrMalformedTransaction)
+	}
+	t.Address = *dec.Address
+	t.StorageKeys = nil
+	if len(dec.StorageKeys) > 0 {
+		t.StorageKeys = dec.StorageKeys
+	}
+	return nil
+}
+
+func missingTxField(name string) error {
+	return fmt.Errorf("%w: missing required field %q", ErrMalformedTransaction, name)
+}
+
+func u64JSON(v uint64) *hexutil.Uint64 {
+	n := hexutil.Uint64(v)
+	return &n
+}
+
+func u256JSON(v uint256.Int) *hexutil.U256 {
+	n := hexutil.U256(v)
+	return &n
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+var (
+	// ErrGasUintOverflow is returned when gas arithmetic overflows a uint64
+	ErrGasUintOverflow = errors.New("gas uint64 overflow")
+
+	// ErrGasUintUnderflow is returned when gas arithmetic would go below zero
+	ErrGasUintUnderflow = errors.New("gas uint64 underflow")
+
+	// ErrWeiOverflow is returned when wei arithmetic overflows 256 bits
+	ErrWeiOverflow = errors.New("wei amount overflows 256 bits")
+
+	// ErrWeiUnderflow is returned when wei arithmetic would go below zero
+	ErrWeiUnderflow = errors.New("wei amount underflow")
+)
+
+// SafeAdd returns x + y or ErrWeiOverflow
+func SafeAdd(x, y uint256.Int) (uint256.Int, error) {
+	z, overflow := x.AddOverflow(y)
+	if overflow {
+		return uint256.Zero, fmt.Errorf("%w: %s + %s", ErrWeiOverflow, x, y)
+	}
+	return z, nil
+}
+
+// SafeSub returns x - y or ErrWeiUnderflow
+func SafeSub(x, y uint256.Int) (uint256.Int, error) {
+	z, underflow := x.SubUnderflow(y)
+	if underflow {
+		return uint256.Zero, fmt.Errorf("%w: %s - %s", ErrWeiUnderflow, x, y)
+	}
+	return z, nil
+}
+
+// SafeMul returns x * y or ErrWeiOverflow
+func SafeMul(x, y uint256.Int) (uint256.Int, error) {
+	z, overflow := x.MulOverflow(y)
+	if overflow {
+		return uint256.Zero, fmt.Errorf("%w: %s * %s", ErrWeiOverflow, x, y)
+	}
+	return z, nil
+}
+
+// GasCost returns gas * price, the wei charged for gas units at a per-gas price
+func GasCost(gas uint64, price uint256.Int) (uint256.Int, error) {
+	return SafeMul(uint256.NewInt(gas), price)
+}
+
+// SafeAddGas returns a + b or ErrGasUintOverflow
+func SafeAddGas(a, b uint64) (uint64, error) {
+	if a > ^uint64(0)-b {
+		return 0, fmt.Errorf("%w: %d + %d", ErrGasUintOverflow, a, b)
+	}
+	return a + b, nil
+}
+
+// SafeSubGas returns a - b or ErrGasUintUnderflow
+func SafeSubGas(a, b uint64) (uint64, error) {
+	if b > a {
+		return 0, fmt.Errorf("%w: %d - %d", ErrGasUintUnderflow, a, b)
+	}
+	return a - b, nil
+}
+
+// SafeMulGas returns a * b or ErrGasUintOverflow
+func SafeMulGas(a, b uint64) (uint64, error) {
+	if a != 0 && b > ^uint64(0)/a {
+		return 0, fmt.Errorf("%w: %d * %d", ErrGasUintOverflow, a, b)
+	}
+	return a * b, nil
+}
@@ -0,0 +1,135 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/rlp"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// ResourceVector holds a gas amount per resource, indexed by constants.Resource
+type ResourceVector [constants.NumResources]uint64
+
+// FeeVector holds a wei amount per resource, indexed by constants.Resource
+type FeeVector [constants.NumResources]uint256.Int
+
+// Add returns v + w, failing if any resource overflows
+func (v ResourceVector) Add(w ResourceVector) (ResourceVector, error) {
+	var sum ResourceVector
+	for r := range v {
+		var err error
+		if sum[r], err = SafeAddGas(v[r], w[r]); err != nil {
+			return ResourceVector{}, err
+		}
+	}
+	return sum, nil
+}
+
+// IsMultidimensional reports whether the block prices gas per resource. The
+// EIP-1559 rule never takes a non-zero base fee to zero, so multidimensional
+// blocks always carry non-zero resource base fees.
+func (b *Block) IsMultidimensional() bool {
+	return b.ResourceBaseFees != FeeVector{}
+}
+
+// ResourceGasLimit splits the transaction's gas limit across resources: calldata gas,
+// the rest of the gas limit as execution gas, and blob gas. Calldata and blob gas
+// are fixed by the transaction; the execution gas actually used is only known
+// once it runs (see executor.ExecutionResult).
+func (tx *Transaction) ResourceGasLimit() (ResourceVector, error) {
+	calldataGas, err := tx.CalldataGas()
+	if err != nil {
+		return ResourceVector{}, err
+	}
+	executionGas, err := SafeSubGas(tx.GasLimit, calldataGas)
+	if err != nil {
+		return ResourceVector{}, &IntrinsicGasError{GasLimit: tx.GasLimit, IntrinsicGas: calldataGas}
+	}
+
+	var gas ResourceVector
+	gas[constants.ExecutionResource] = executionGas
+	gas[constants.CalldataResource] = calldataGas
+	gas[constants.BlobResource] = tx.BlobGas()
+	return gas, nil
+}
+
+// MaxFeesPerResource returns the fee cap of each resource: ResourceFeeCaps for resource
+// fee transactions. Older types have no per-resource fields, so, as EIP-7706 does for
+// them, the gas fee cap covers execution and calldata and MaxFeePerBlobGas covers blobs.
+func (tx *Transaction) MaxFeesPerResource() FeeVector {
+	if tx.Type == ResourceFeeTxType {
+		return tx.ResourceFeeCaps
+	}
+	var caps FeeVector
+	caps[constants.ExecutionResource] = tx.GasFeeCap()
+	caps[constants.CalldataResource] = tx.GasFeeCap()
+	caps[constants.BlobResource] = tx.MaxFeePerBlobGas
+	return caps
+}
+
+// ValidateResourceFees checks that every resource the transaction uses has a fee cap covering its base fee
+func (tx *Transaction) ValidateResourceFees(baseFees FeeVector) error {
+	gas, err := tx.ResourceGasLimit()
+	if err != nil {
+		return err
+	}
+	caps := tx.MaxFeesPerResource()
+	for r := range constants.NumResources {
+		if r != int(constants.ExecutionResource) && gas[r] == 0 {
+			continue
+		}
+		if caps[r].Lt(baseFees[r]) {
+			return &ResourceFeeCapError{Resource: constants.Resource(r), MaxFee: caps[r], BaseFee: baseFees[r]}
+		}
+	}
+	return nil
+}
+
+// maxResourceCost returns the most a resource fee transaction can be charged: the
+// gas limit of every resource at its fee cap, plus the value
+func (tx *Transaction) maxResourceCost() (uint256.Int, error) {
+	gas, err := tx.ResourceGasLimit()
+	if err != nil {
+		return uint256.Zero, err
+	}
+	total := tx.Value
+	for r := range constants.NumResources {
+		cost, err := GasCost(gas[r], tx.ResourceFeeCaps[r])
+		if err != nil {
+			return uint256.Zero, err
+		}
+		if total, err = SafeAdd(total, cost); err != nil {
+			return uint256.Zero, err
+		}
+	}
+	return total, nil
+}
+
+// encode returns the RLP list of the per-resource amounts
+func (v FeeVector) encode() []byte {
+	fields := make([][]byte, len(v))
+	for r, fee := range v {
+		fields[r] = encodeUint256(fee)
+	}
+	return rlp.EncodeList(fields...)
+}
+
+// decodeFeeVectorInto decodes an RLP list holding exactly one amount per resource
+func decodeFeeVectorInto(dst *FeeVector) func([]byte) error {
+	return func(b []byte) error {
+		elems, err := rlp.ListElements(b)
+		if err != nil {
+			return err
+		}
+		if len(elems) != constants.NumResources {
+			return fmt.Errorf("expected %d resources, got %d", constants.NumResources, len(elems))
+		}
+		for r, elem := range elems {
+			if err := decodeUint256Into(&dst[r])(elem); err != nil {
+				return fmt.Errorf("%s: %w", constants.Resource(r), err)
+			}
+		}
+		return nil
+	}
+}
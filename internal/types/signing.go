@@ -0,0 +1,115 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/crypto"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/rlp"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// PubkeyToAddress derives the account address of a public key: the last 20 bytes of Keccak-256(X || Y)
+func PubkeyToAddress(pub crypto.PublicKey) Address {
+	return BytesToAddress(crypto.Keccak256(pub.Bytes()[1:])[12:])
+}
+
+// SigningHash returns the hash signed by the sender:
+//
+//	legacy (EIP-155): keccak256(rlp([nonce, gasPrice, gasLimit, to, value, data, chainId, 0, 0]))
+//	legacy (pre-155): keccak256(rlp([nonce, gasPrice, gasLimit, to, value, data]))
+//	typed:            keccak256(type || rlp(unsigned payload fields))
+func (tx *Transaction) SigningHash() Hash {
+	fields := tx.unsignedFields()
+	if tx.Type == LegacyTxType {
+		if tx.ChainID != 0 {
+			fields = append(fields, rlp.EncodeUint64(tx.ChainID), rlp.EmptyString, rlp.EmptyString)
+		}
+		return BytesToHash(crypto.Keccak256(rlp.EncodeList(fields...)))
+	}
+	return BytesToHash(crypto.Keccak256([]byte{tx.Type}, rlp.EncodeList(fields...)))
+}
+
+// Sign signs the transaction with key, setting V, R, S and From.
+// Typed transactions store the y-parity in V; legacy transactions store
+// chainId*2 + 35 + yParity (EIP-155), or 27 + yParity when ChainID is zero.
+func (tx *Transaction) Sign(key *crypto.PrivateKey) error {
+	hash := tx.SigningHash()
+	sig, err := crypto.Sign(hash[:], key)
+	if err != nil {
+		return err
+	}
+
+	v := uint256.NewInt(uint64(sig[64]))
+	if tx.Type == LegacyTxType {
+		if tx.ChainID == 0 {
+			v = v.Add(uint256.NewInt(27))
+		} else {
+			v = v.Add(uint256.NewInt(tx.ChainID).Mul(uint256.NewInt(2)).Add(uint256.NewInt(35)))
+		}
+	}
+
+	tx.R = uint256.FromBytes(sig[:32])
+	tx.S = uint256.FromBytes(sig[32:64])
+	tx.V = v
+	tx.From = PubkeyToAddress(key.PublicKey)
+	return nil
+}
+
+// Sender recovers the address that signed the transaction from (V, R, S).
+// V must carry a y-parity consistent with the transaction type and ChainID,
+// and S must be in the lower half of the curve order (EIP-2).
+func (tx *Transaction) Sender() (Address, error) {
+	parity, err := tx.yParity()
+	if err != nil {
+		return Address{}, err
+	}
+
+	r, s := tx.R.ToBig(), tx.S.ToBig()
+	if !crypto.ValidateSignatureValues(parity, r, s, true) {
+		if s.Cmp(crypto.Secp256k1HalfN) > 0 {
+			return Address{}, &InvalidSignatureError{Reason: "s is in the upper half of the curve order"}
+		}
+		return Address{}, &InvalidSignatureError{Reason: "r or s out of range"}
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	r32, s32 := tx.R.Bytes32(), tx.S.Bytes32()
+	copy(sig[:32], r32[:])
+	copy(sig[32:64], s32[:])
+	sig[64] = parity
+
+	hash := tx.SigningHash()
+	pub, err := crypto.RecoverPubkey(hash[:], sig)
+	if err != nil {
+		return Address{}, &InvalidSignatureError{Reason: err.Error()}
+	}
+	return PubkeyToAddress(*pub), nil
+}
+
+// yParity extracts the recovery bit from V according to the transaction type
+func (tx *Transaction) yParity() (byte, error) {
+	if !tx.V.IsUint64() {
+		return 0, &InvalidSignatureError{Reason: fmt.Sprintf("v %s out of range", tx.V)}
+	}
+	v := tx.V.Uint64()
+
+	if tx.Type != LegacyTxType {
+		if v > 1 {
+			return 0, &InvalidSignatureError{Reason: fmt.Sprintf("y-parity %d is not 0 or 1", v)}
+		}
+		return byte(v), nil
+	}
+
+	if tx.ChainID == 0 {
+		if v != 27 && v != 28 {
+			return 0, &InvalidSignatureError{Reason: fmt.Sprintf("v %d is not 27 or 28", v)}
+		}
+		return byte(v - 27), nil
+	}
+
+	base := tx.ChainID*2 + 35
+	if v != base && v != base+1 {
+		return 0, &InvalidSignatureError{Reason: fmt.Sprintf("v %d does not encode chain ID %d", v, tx.ChainID)}
+	}
+	return byte(v - base), nil
+}
@@ -1,57 +1,241 @@
 package types
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
 
-// the Transaction represent an EIP-1559 transaction
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// the Transaction represent a legacy, EIP-2930, EIP-1559, EIP-4844 or resource fee transaction.
+// Legacy and access-list transactions pay a single GasPrice; EIP-1559 and blob
+// transactions set MaxPriorityFeePerGas and MaxFeePerGas instead, and resource
+// fee transactions cap each resource separately with ResourceFeeCaps.
 type Transaction struct {
+	Type                 byte // LegacyTxType, AccessListTxType, DynamicFeeTxType, BlobTxType or ResourceFeeTxType
 	ChainID              uint64
 	Nonce                uint64
-	MaxPriorityFeePerGas uint64 // Tip to miner
-	MaxFeePerGas         uint64 // Max total fee willing to pay
+	GasPrice             uint256.Int // Legacy and access-list transactions only
+	MaxPriorityFeePerGas uint256.Int // Tip to miner
+	MaxFeePerGas         uint256.Int // Max total fee willing to pay
 	GasLimit             uint64
-	To                   string
-	Value                uint64
+	To                   *Address // nil for contract creation
+	Value                uint256.Int
 	Data                 []byte
-	From                 string
+	AccessList           AccessList  // EIP-2930 pre-declared addresses and storage keys
+	MaxFeePerBlobGas     uint256.Int // EIP-4844 max blob fee willing to pay
+	BlobHashes           []Hash      // EIP-4844 versioned hashes of the carried blobs
+	ResourceFeeCaps      FeeVector   // Resource fee transactions only: max fee per gas of each resource
+	From                 Address
+
+	// Signature values
+	V uint256.Int
+	R uint256.Int
+	S uint256.Int
 }
 
-// EffectiveGasPrice calculates the actual gas price paid
-func (tx *Transaction) EffectiveGasPrice(baseFee uint64) uint64 {
+// GasFeeCap returns the most the transaction pays per gas: MaxFeePerGas, GasPrice for
+// pre-1559 types, or the execution fee cap for resource fee transactions
+func (tx *Transaction) GasFeeCap() uint256.Int {
+	switch {
+	case tx.hasGasPrice():
+		return tx.GasPrice
+	case tx.Type == ResourceFeeTxType:
+		return tx.ResourceFeeCaps[constants.ExecutionResource]
+	}
+	return tx.MaxFeePerGas
+}
 
-	// priority fee is capped by (maxFee - baseFee)
-	priorityFee := tx.MaxPriorityFeePerGas
-	if tx.MaxFeePerGas-baseFee < priorityFee {
-		priorityFee = tx.MaxFeePerGas - baseFee
+// GasTipCap returns the most the transaction tips per gas: MaxPriorityFeePerGas, or GasPrice for pre-1559 types
+func (tx *Transaction) GasTipCap() uint256.Int {
+	if tx.hasGasPrice() {
+		return tx.GasPrice
 	}
+	return tx.MaxPriorityFeePerGas
+}
+
+func (tx *Transaction) hasGasPrice() bool {
+	return tx.Type == LegacyTxType || tx.Type == AccessListTxType
+}
 
-	return priorityFee + baseFee
+// BlobGas returns the blob gas the transaction consumes: BlobGasPerBlob per blob hash
+func (tx *Transaction) BlobGas() uint64 {
+	return uint64(len(tx.BlobHashes)) * constants.BlobGasPerBlob
 }
 
-func (tx *Transaction) EffectivePriorityFee(baseFee uint64) uint64 {
-	priorityFee := tx.MaxPriorityFeePerGas
-	if tx.MaxFeePerGas-baseFee < priorityFee {
-		priorityFee = tx.MaxFeePerGas - baseFee
+// EffectiveGasPrice calculates the actual gas price paid
+func (tx *Transaction) EffectiveGasPrice(baseFee uint256.Int) (uint256.Int, error) {
+	priorityFee, err := tx.EffectivePriorityFee(baseFee)
+	if err != nil {
+		return uint256.Zero, err
 	}
-	return priorityFee
+	return SafeAdd(priorityFee, baseFee)
+}
+
+// EffectivePriorityFee returns the tip per gas paid to the miner, capped by (maxFee - baseFee)
+func (tx *Transaction) EffectivePriorityFee(baseFee uint256.Int) (uint256.Int, error) {
+	feeCap := tx.GasFeeCap()
+	headroom, err := SafeSub(feeCap, baseFee)
+	if err != nil {
+		return uint256.Zero, &FeeCapError{MaxFeePerGas: feeCap, BaseFee: baseFee}
+	}
+	return uint256.MinOf(tx.GasTipCap(), headroom), nil
 }
 
 // Validate performs basic transaction validation
-func (tx *Transaction) Validate(baseFee uint64) error {
-	if tx.MaxFeePerGas < baseFee {
-		return fmt.Errorf("max fee pre gas %d less than base fee %d", tx.MaxFeePerGas, baseFee)
+func (tx *Transaction) Validate(baseFee uint256.Int) error {
+	if !tx.isKnownType() {
+		return fmt.Errorf("%w: 0x%02x", ErrTxTypeNotSupported, tx.Type)
+	}
+
+	feeCap, tipCap := tx.GasFeeCap(), tx.GasTipCap()
+	if feeCap.Lt(baseFee) {
+		return &FeeCapError{MaxFeePerGas: feeCap, BaseFee: baseFee}
 	}
 
-	if tx.MaxFeePerGas < tx.MaxPriorityFeePerGas {
-		return fmt.Errorf("max fee pre gas %d less than max priority fee %d", tx.MaxFeePerGas, tx.MaxPriorityFeePerGas)
+	if feeCap.Lt(tipCap) {
+		return &TipAboveFeeCapError{MaxPriorityFeePerGas: tipCap, MaxFeePerGas: feeCap}
+	}
+
+	if tx.Type == LegacyTxType && len(tx.AccessList) > 0 {
+		return fmt.Errorf("%w: legacy transactions cannot carry an access list", ErrMalformedTransaction)
+	}
+
+	// Resource fee transactions may carry blobs, priced by their blob fee cap
+	switch {
+	case tx.Type == BlobTxType || (tx.Type == ResourceFeeTxType && len(tx.BlobHashes) > 0):
+		if err := tx.validateBlobs(); err != nil {
+			return err
+		}
+	case len(tx.BlobHashes) > 0:
+		return fmt.Errorf("%w: only blob and resource fee transactions can carry blob hashes", ErrMalformedTransaction)
 	}
 
 	if tx.GasLimit == 0 {
-		return fmt.Errorf("gas limit cannot be zero")
+		return ErrZeroGasLimit
+	}
+
+	// EIP-3860 caps the size of contract creation code
+	if tx.To == nil && len(tx.Data) > constants.MaxInitCodeSize {
+		return &InitCodeSizeError{Size: len(tx.Data), Max: constants.MaxInitCodeSize}
+	}
+
+	return nil
+}
+
+// ValidateChainID checks that the transaction was signed for the chain with the given ID.
+// The chain ID is part of the signing hash, so a signature cannot be moved to another
+// chain either. Unprotected legacy transactions (ChainID 0) predate EIP-155 and are
+// valid on every chain.
+func (tx *Transaction) ValidateChainID(chainID uint64) error {
+	if tx.Type == LegacyTxType && tx.ChainID == 0 {
+		return nil
+	}
+	if tx.ChainID != chainID {
+		return &ChainIDError{Expected: chainID, Got: tx.ChainID}
+	}
+	return nil
+}
+
+// validateBlobs checks the EIP-4844 rules: a recipient, at least one blob, KZG-versioned hashes
+// and no more blob gas than a block can hold
+func (tx *Transaction) validateBlobs() error {
+	if tx.To == nil {
+		return ErrBlobTxCreate
+	}
+	if len(tx.BlobHashes) == 0 {
+		return ErrMissingBlobHashes
+	}
+	for i, h := range tx.BlobHashes {
+		if h[0] != constants.BlobHashVersionKZG {
+			return fmt.Errorf("%w: hash %d has version 0x%02x", ErrBlobHashVersion, i, h[0])
+		}
 	}
+	if blobGas := tx.BlobGas(); blobGas > constants.MaxBlobGasPerBlock {
+		return &BlobGasLimitExceededError{BlobGasUsed: blobGas, MaxBlobGas: constants.MaxBlobGasPerBlock}
+	}
+	return nil
+}
 
+// ValidateBlobFee checks that MaxFeePerBlobGas covers the block's blob base fee; non-blob transactions always pass
+func (tx *Transaction) ValidateBlobFee(blobBaseFee uint256.Int) error {
+	if tx.Type != BlobTxType {
+		return nil
+	}
+	if tx.MaxFeePerBlobGas.Lt(blobBaseFee) {
+		return &BlobFeeCapError{MaxFeePerBlobGas: tx.MaxFeePerBlobGas, BlobBaseFee: blobBaseFee}
+	}
 	return nil
 }
 
-func (tx *Transaction) MaxCost() uint64 {
-	return tx.GasLimit*tx.MaxFeePerGas + tx.Value
+// IntrinsicGas returns the gas charged before execution: the base transaction
+// cost, the calldata cost, the access list cost and, for contract creation,
+// 32000 plus 2 per word of initcode (EIP-3860)
+func (tx *Transaction) IntrinsicGas() (uint64, error) {
+	dataGas, err := tx.CalldataGas()
+	if err != nil {
+		return 0, err
+	}
+	accessListGas, err := tx.AccessList.IntrinsicGas()
+	if err != nil {
+		return 0, err
+	}
+
+	gas, err := SafeAddGas(constants.TxGas, dataGas)
+	if err != nil {
+		return 0, err
+	}
+	if gas, err = SafeAddGas(gas, accessListGas); err != nil {
+		return 0, err
+	}
+	if tx.To != nil {
+		return gas, nil
+	}
+
+	words := (uint64(len(tx.Data)) + 31) / 32
+	initCodeGas, err := SafeMulGas(words, constants.InitCodeWordGas)
+	if err != nil {
+		return 0, err
+	}
+	if gas, err = SafeAddGas(gas, constants.TxCreateGas); err != nil {
+		return 0, err
+	}
+	return SafeAddGas(gas, initCodeGas)
+}
+
+// CalldataGas returns the gas charged for the transaction's calldata:
+// 4 per zero byte and 16 per non-zero byte
+func (tx *Transaction) CalldataGas() (uint64, error) {
+	zeros := uint64(bytes.Count(tx.Data, []byte{0}))
+	zeroGas, err := SafeMulGas(zeros, constants.TxDataZeroGas)
+	if err != nil {
+		return 0, err
+	}
+	nonZeroGas, err := SafeMulGas(uint64(len(tx.Data))-zeros, constants.TxDataNonZeroGas)
+	if err != nil {
+		return 0, err
+	}
+	return SafeAddGas(zeroGas, nonZeroGas)
+}
+
+// MaxCost returns the most the sender can be charged: GasLimit * GasFeeCap + BlobGas * MaxFeePerBlobGas + Value.
+// A resource fee transaction prepays each resource's gas limit at that resource's fee cap instead.
+func (tx *Transaction) MaxCost() (uint256.Int, error) {
+	if tx.Type == ResourceFeeTxType {
+		return tx.maxResourceCost()
+	}
+	gasCost, err := GasCost(tx.GasLimit, tx.GasFeeCap())
+	if err != nil {
+		return uint256.Zero, err
+	}
+	blobCost, err := GasCost(tx.BlobGas(), tx.MaxFeePerBlobGas)
+	if err != nil {
+		return uint256.Zero, err
+	}
+	total, err := SafeAdd(gasCost, blobCost)
+	if err != nil {
+		return uint256.Zero, err
+	}
+	return SafeAdd(total, tx.Value)
 }
@@ -0,0 +1,369 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/crypto"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/rlp"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// EIP-2718 transaction type bytes. Legacy transactions predate EIP-2718 and
+// are encoded as a bare RLP list without a type byte.
+const (
+	LegacyTxType     byte = 0x00
+	AccessListTxType byte = 0x01 // EIP-2930
+	DynamicFeeTxType byte = 0x02 // EIP-1559
+	BlobTxType       byte = 0x03 // EIP-4844
+
+	// ResourceFeeTxType caps the fee of every resource separately, in the style of EIP-7706
+	ResourceFeeTxType byte = 0x04
+)
+
+var (
+	// ErrTxTypeNotSupported is returned when decoding an envelope with an unknown type byte
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+
+	// ErrMalformedTransaction is returned when a transaction payload is not well formed
+	ErrMalformedTransaction = errors.New("malformed transaction")
+)
+
+// MarshalBinary returns the wire encoding of the transaction:
+//
+//	legacy: rlp([nonce, gasPrice, gasLimit, to, value, data, v, r, s])
+//	0x01 || rlp([chainId, nonce, gasPrice, gasLimit, to, value, data, accessList, v, r, s])
+//	0x02 || rlp([chainId, nonce, maxPriorityFeePerGas, maxFeePerGas, gasLimit, to, value, data, accessList, v, r, s])
+//	0x03 || rlp([chainId, nonce, maxPriorityFeePerGas, maxFeePerGas, gasLimit, to, value, data, accessList,
+//	             maxFeePerBlobGas, blobVersionedHashes, v, r, s])
+//	0x04 || rlp([chainId, nonce, maxPriorityFeePerGas, gasLimit, to, value, data, accessList,
+//	             [execution, calldata, blob max fees per gas], blobVersionedHashes, v, r, s])
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if !tx.isKnownType() {
+		return nil, fmt.Errorf("%w: 0x%02x", ErrTxTypeNotSupported, tx.Type)
+	}
+
+	fields := append(tx.unsignedFields(), encodeUint256(tx.V), encodeUint256(tx.R), encodeUint256(tx.S))
+	payload := rlp.EncodeList(fields...)
+	if tx.Type == LegacyTxType {
+		return payload, nil
+	}
+	return append([]byte{tx.Type}, payload...), nil
+}
+
+// UnmarshalBinary decodes a legacy RLP transaction or an EIP-2718 typed envelope.
+// The sender is not part of the encoding, so From is left zero.
+func (tx *Transaction) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		return fmt.Errorf("%w: empty input", ErrMalformedTransaction)
+	}
+
+	var dec Transaction
+	payload := b
+	switch {
+	case b[0] >= 0xc0:
+		dec.Type = LegacyTxType
+	case b[0] == AccessListTxType || b[0] == DynamicFeeTxType || b[0] == BlobTxType || b[0] == ResourceFeeTxType:
+		dec.Type = b[0]
+		payload = b[1:]
+	default:
+		return fmt.Errorf("%w: 0x%02x", ErrTxTypeNotSupported, b[0])
+	}
+
+	elems, err := rlp.ListElements(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedTransaction, err)
+	}
+
+	fields := dec.fieldDecoders()
+	if len(elems) != len(fields) {
+		return fmt.Errorf("%w: expected %d fields, got %d", ErrMalformedTransaction, len(fields), len(elems))
+	}
+	for i, field := range fields {
+		if err := field.decode(elems[i]); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrMalformedTransaction, field.name, err)
+		}
+	}
+
+	if dec.Type == LegacyTxType {
+		chainID, err := legacyChainID(dec.V)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrMalformedTransaction, err)
+		}
+		dec.ChainID = chainID
+	}
+
+	*tx = dec
+	return nil
+}
+
+// Hash returns the transaction hash: Keccak-256 of the wire encoding
+func (tx *Transaction) Hash() Hash {
+	enc, _ := tx.MarshalBinary()
+	return BytesToHash(crypto.Keccak256(enc))
+}
+
+func (tx *Transaction) isKnownType() bool {
+	switch tx.Type {
+	case LegacyTxType, AccessListTxType, DynamicFeeTxType, BlobTxType, ResourceFeeTxType:
+		return true
+	}
+	return false
+}
+
+// unsignedFields returns the encoded payload fields that precede the signature
+func (tx *Transaction) unsignedFields() [][]byte {
+	var to []byte
+	if tx.To != nil {
+		to = tx.To[:]
+	}
+
+	switch tx.Type {
+	case LegacyTxType:
+		return [][]byte{
+			rlp.EncodeUint64(tx.Nonce),
+			encodeUint256(tx.GasPrice),
+			rlp.EncodeUint64(tx.GasLimit),
+			rlp.EncodeBytes(to),
+			encodeUint256(tx.Value),
+			rlp.EncodeBytes(tx.Data),
+		}
+	case AccessListTxType:
+		return [][]byte{
+			rlp.EncodeUint64(tx.ChainID),
+			rlp.EncodeUint64(tx.Nonce),
+			encodeUint256(tx.GasPrice),
+			rlp.EncodeUint64(tx.GasLimit),
+			rlp.EncodeBytes(to),
+			encodeUint256(tx.Value),
+			rlp.EncodeBytes(tx.Data),
+			tx.AccessList.encode(),
+		}
+	case BlobTxType:
+		return [][]byte{
+			rlp.EncodeUint64(tx.ChainID),
+			rlp.EncodeUint64(tx.Nonce),
+			encodeUint256(tx.MaxPriorityFeePerGas),
+			encodeUint256(tx.MaxFeePerGas),
+			rlp.EncodeUint64(tx.GasLimit),
+			rlp.EncodeBytes(to),
+			encodeUint256(tx.Value),
+			rlp.EncodeBytes(tx.Data),
+			tx.AccessList.encode(),
+			encodeUint256(tx.MaxFeePerBlobGas),
+			encodeHashes(tx.BlobHashes),
+		}
+	case ResourceFeeTxType:
+		return [][]byte{
+			rlp.EncodeUint64(tx.ChainID),
+			rlp.EncodeUint64(tx.Nonce),
+			encodeUint256(tx.MaxPriorityFeePerGas),
+			rlp.EncodeUint64(tx.GasLimit),
+			rlp.EncodeBytes(to),
+			encodeUint256(tx.Value),
+			rlp.EncodeBytes(tx.Data),
+			tx.AccessList.encode(),
+			tx.ResourceFeeCaps.encode(),
+			encodeHashes(tx.BlobHashes),
+		}
+	default:
+		return [][]byte{
+			rlp.EncodeUint64(tx.ChainID),
+			rlp.EncodeUint64(tx.Nonce),
+			encodeUint256(tx.MaxPriorityFeePerGas),
+			encodeUint256(tx.MaxFeePerGas),
+			rlp.EncodeUint64(tx.GasLimit),
+			rlp.EncodeBytes(to),
+			encodeUint256(tx.Value),
+			rlp.EncodeBytes(tx.Data),
+			tx.AccessList.encode(),
+		}
+	}
+}
+
+type fieldDecoder struct {
+	name   string
+	decode func([]byte) error
+}
+
+// fieldDecoders returns decoders for every encoded field of tx.Type, in wire order
+func (tx *Transaction) fieldDecoders() []fieldDecoder {
+	signature := []fieldDecoder{
+		{"v", decodeUint256Into(&tx.V)},
+		{"r", decodeUint256Into(&tx.R)},
+		{"s", decodeUint256Into(&tx.S)},
+	}
+
+	switch tx.Type {
+	case LegacyTxType:
+		return append([]fieldDecoder{
+			{"nonce", decodeUint64Into(&tx.Nonce)},
+			{"gasPrice", decodeUint256Into(&tx.GasPrice)},
+			{"gasLimit", decodeUint64Into(&tx.GasLimit)},
+			{"to", decodeToInto(&tx.To)},
+			{"value", decodeUint256Into(&tx.Value)},
+			{"data", decodeBytesInto(&tx.Data)},
+		}, signature...)
+	case AccessListTxType:
+		return append([]fieldDecoder{
+			{"chainId", decodeUint64Into(&tx.ChainID)},
+			{"nonce", decodeUint64Into(&tx.Nonce)},
+			{"gasPrice", decodeUint256Into(&tx.GasPrice)},
+			{"gasLimit", decodeUint64Into(&tx.GasLimit)},
+			{"to", decodeToInto(&tx.To)},
+			{"value", decodeUint256Into(&tx.Value)},
+			{"data", decodeBytesInto(&tx.Data)},
+			{"accessList", decodeAccessListInto(&tx.AccessList)},
+		}, signature...)
+	case BlobTxType:
+		return append([]fieldDecoder{
+			{"chainId", decodeUint64Into(&tx.ChainID)},
+			{"nonce", decodeUint64Into(&tx.Nonce)},
+			{"maxPriorityFeePerGas", decodeUint256Into(&tx.MaxPriorityFeePerGas)},
+			{"maxFeePerGas", decodeUint256Into(&tx.MaxFeePerGas)},
+			{"gasLimit", decodeUint64Into(&tx.GasLimit)},
+			{"to", decodeToInto(&tx.To)},
+			{"value", decodeUint256Into(&tx.Value)},
+			{"data", decodeBytesInto(&tx.Data)},
+			{"accessList", decodeAccessListInto(&tx.AccessList)},
+			{"maxFeePerBlobGas", decodeUint256Into(&tx.MaxFeePerBlobGas)},
+			{"blobVersionedHashes", decodeHashesInto(&tx.BlobHashes)},
+		}, signature...)
+	case ResourceFeeTxType:
+		return append([]fieldDecoder{
+			{"chainId", decodeUint64Into(&tx.ChainID)},
+			{"nonce", decodeUint64Into(&tx.Nonce)},
+			{"maxPriorityFeePerGas", decodeUint256Into(&tx.MaxPriorityFeePerGas)},
+			{"gasLimit", decodeUint64Into(&tx.GasLimit)},
+			{"to", decodeToInto(&tx.To)},
+			{"value", decodeUint256Into(&tx.Value)},
+			{"data", decodeBytesInto(&tx.Data)},
+			{"accessList", decodeAccessListInto(&tx.AccessList)},
+			{"maxFeesPerGas", decodeFeeVectorInto(&tx.ResourceFeeCaps)},
+			{"blobVersionedHashes", decodeHashesInto(&tx.BlobHashes)},
+		}, signature...)
+	default:
+		return append([]fieldDecoder{
+			{"chainId", decodeUint64Into(&tx.ChainID)},
+			{"nonce", decodeUint64Into(&tx.Nonce)},
+			{"maxPriorityFeePerGas", decodeUint256Into(&tx.MaxPriorityFeePerGas)},
+			{"maxFeePerGas", decodeUint256Into(&tx.MaxFeePerGas)},
+			{"gasLimit", decodeUint64Into(&tx.GasLimit)},
+			{"to", decodeToInto(&tx.To)},
+			{"value", decodeUint256Into(&tx.Value)},
+			{"data", decodeBytesInto(&tx.Data)},
+			{"accessList", decodeAccessListInto(&tx.AccessList)},
+		}, signature...)
+	}
+}
+
+// legacyChainID extracts the chain ID from a legacy V value: 27/28 before
+// EIP-155, otherwise chainId*2 + 35 + yParity
+func legacyChainID(v uint256.Int) (uint64, error) {
+	if !v.IsUint64() {
+		return 0, fmt.Errorf("v %s out of range", v)
+	}
+	switch raw := v.Uint64(); {
+	case raw == 27 || raw == 28:
+		return 0, nil
+	case raw >= 35:
+		return (raw - 35) / 2, nil
+	default:
+		return 0, fmt.Errorf("v %d is neither 27/28 nor EIP-155 encoded", raw)
+	}
+}
+
+func decodeUint64Into(dst *uint64) func([]byte) error {
+	return func(b []byte) (err error) {
+		*dst, _, err = rlp.SplitUint64(b)
+		return err
+	}
+}
+
+func decodeUint256Into(dst *uint256.Int) func([]byte) error {
+	return func(b []byte) error {
+		content, _, err := rlp.SplitInt(b)
+		if err != nil {
+			return err
+		}
+		if len(content) > 32 {
+			return uint256.ErrOverflow
+		}
+		*dst = uint256.FromBytes(content)
+		return nil
+	}
+}
+
+func decodeBytesInto(dst *[]byte) func([]byte) error {
+	return func(b []byte) error {
+		content, _, err := rlp.SplitString(b)
+		if err != nil {
+			return err
+		}
+		if len(content) > 0 {
+			*dst = append([]byte(nil), content...)
+		}
+		return nil
+	}
+}
+
+// decodeToInto decodes the recipient: empty for contract creation, otherwise exactly 20 bytes
+func decodeToInto(dst **Address) func([]byte) error {
+	return func(b []byte) error {
+		content, _, err := rlp.SplitString(b)
+		if err != nil {
+			return err
+		}
+		switch len(content) {
+		case 0:
+			*dst = nil
+		case AddressLength:
+			to := BytesToAddress(content)
+			*dst = &to
+		default:
+			return fmt.Errorf("%w: %d bytes", ErrInvalidAddress, len(content))
+		}
+		return nil
+	}
+}
+
+// encodeHashes returns the RLP list of the blob versioned hashes
+func encodeHashes(hashes []Hash) []byte {
+	fields := make([][]byte, len(hashes))
+	for i, h := range hashes {
+		fields[i] = rlp.EncodeBytes(h[:])
+	}
+	return rlp.EncodeList(fields...)
+}
+
+// decodeHashesInto decodes a list of 32-byte hashes, keeping nil for an empty list
+func decodeHashesInto(dst *[]Hash) func([]byte) error {
+	return func(b []byte) error {
+		elems, err := rlp.ListElements(b)
+		if err != nil {
+			return err
+		}
+		var hashes []Hash
+		for i, elem := range elems {
+			content, _, err := rlp.SplitString(elem)
+			if err != nil {
+				return fmt.Errorf("hash %d: %w", i, err)
+			}
+			if len(content) != HashLength {
+				return fmt.Errorf("hash %d: %w: %d bytes", i, ErrInvalidHash, len(content))
+			}
+			hashes = append(hashes, BytesToHash(content))
+		}
+		*dst = hashes
+		return nil
+	}
+}
+
+// DecodeTransaction decodes a raw legacy or EIP-2718 typed transaction
+func DecodeTransaction(b []byte) (*Transaction, error) {
+	tx := new(Transaction)
+	if err := tx.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
@@ -0,0 +1,234 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// Sentinel errors for block validation. Transaction-level errors (nonce,
+// funds, fee caps) live in the types package and are returned unchanged.
+var (
+	ErrInvalidBlockNumber  = errors.New("invalid block number")
+	ErrBadParentHash       = errors.New("invalid parent hash")
+	ErrBadBaseFee          = errors.New("invalid base fee")
+	ErrGasLimitOutOfBounds = errors.New("gas limit out of bounds")
+	ErrInvalidTransaction  = errors.New("invalid transaction")
+	ErrBadExcessBlobGas    = errors.New("invalid excess blob gas")
+	ErrBadBlobGasUsed      = errors.New("invalid blob gas used")
+
+	ErrBadTimestamp     = errors.New("invalid timestamp")
+	ErrFutureBlock      = errors.New("block timestamp in the future")
+	ErrExtraDataTooLong = errors.New("extra data too long")
+	ErrZeroMiner        = errors.New("zero miner address")
+	ErrBadTxCount       = errors.New("transaction count does not match body")
+	ErrBadReceiptCount  = errors.New("receipt count does not match transactions")
+	ErrBadGasUsed       = errors.New("invalid gas used")
+
+	ErrBadResourceBaseFee    = errors.New("invalid resource base fee")
+	ErrBadResourceGasUsed    = errors.New("invalid resource gas used")
+	ErrResourceLimitExceeded = errors.New("resource gas limit exceeded")
+)
+
+// BlockNumberError reports a block that does not directly follow its parent
+type BlockNumberError struct {
+	Expected uint64
+	Got      uint64
+}
+
+func (e *BlockNumberError) Error() string {
+	return fmt.Sprintf("%v: expected %d, got %d", ErrInvalidBlockNumber, e.Expected, e.Got)
+}
+
+func (e *BlockNumberError) Unwrap() error { return ErrInvalidBlockNumber }
+
+// ParentHashError reports a block whose parent hash does not match its parent
+type ParentHashError struct {
+	Expected types.Hash
+	Got      types.Hash
+}
+
+func (e *ParentHashError) Error() string {
+	return fmt.Sprintf("%v: expected %s, got %s", ErrBadParentHash, e.Expected, e.Got)
+}
+
+func (e *ParentHashError) Unwrap() error { return ErrBadParentHash }
+
+// BaseFeeError reports a block base fee that differs from the one derived from its parent
+type BaseFeeError struct {
+	Expected uint256.Int
+	Got      uint256.Int
+}
+
+func (e *BaseFeeError) Error() string {
+	return fmt.Sprintf("%v: expected %s, got %s", ErrBadBaseFee, e.Expected, e.Got)
+}
+
+func (e *BaseFeeError) Unwrap() error { return ErrBadBaseFee }
+
+// GasLimitError reports a block gas limit outside [Min, Max], the range allowed by its parent.
+// On the fork block ParentLimit is the elasticity-adjusted parent limit.
+type GasLimitError struct {
+	ParentLimit uint64
+	Limit       uint64
+	Min         uint64
+	Max         uint64
+	Err         error // Set when the bounds around ParentLimit overflow a uint64
+}
+
+func (e *GasLimitError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%v: bounds around parent %d: %v", ErrGasLimitOutOfBounds, e.ParentLimit, e.Err)
+	}
+	if e.Limit > e.Max {
+		return fmt.Sprintf("%v: gas limit increased too much: parent %d, current %d, max %d",
+			ErrGasLimitOutOfBounds, e.ParentLimit, e.Limit, e.Max)
+	}
+	return fmt.Sprintf("%v: gas limit too low: parent %d, current %d, min %d",
+		ErrGasLimitOutOfBounds, e.ParentLimit, e.Limit, e.Min)
+}
+
+func (e *GasLimitError) Unwrap() []error {
+	if e.Err != nil {
+		return []error{ErrGasLimitOutOfBounds, e.Err}
+	}
+	return []error{ErrGasLimitOutOfBounds}
+}
+
+// ExcessBlobGasError reports a block excess blob gas that differs from the one derived from its parent
+type ExcessBlobGasError struct {
+	Expected uint64
+	Got      uint64
+}
+
+func (e *ExcessBlobGasError) Error() string {
+	return fmt.Sprintf("%v: expected %d, got %d", ErrBadExcessBlobGas, e.Expected, e.Got)
+}
+
+func (e *ExcessBlobGasError) Unwrap() error { return ErrBadExcessBlobGas }
+
+// BlobGasUsedError reports a block blob gas used that differs from the blob gas of its transactions
+type BlobGasUsedError struct {
+	Expected uint64
+	Got      uint64
+}
+
+func (e *BlobGasUsedError) Error() string {
+	return fmt.Sprintf("%v: expected %d, got %d", ErrBadBlobGasUsed, e.Expected, e.Got)
+}
+
+func (e *BlobGasUsedError) Unwrap() error { return ErrBadBlobGasUsed }
+
+// ResourceBaseFeeError reports a resource base fee that differs from the one derived from the parent
+type ResourceBaseFeeError struct {
+	Resource constants.Resource
+	Expected uint256.Int
+	Got      uint256.Int
+}
+
+func (e *ResourceBaseFeeError) Error() string {
+	return fmt.Sprintf("%v: %s expected %s, got %s", ErrBadResourceBaseFee, e.Resource, e.Expected, e.Got)
+}
+
+func (e *ResourceBaseFeeError) Unwrap() error { return ErrBadResourceBaseFee }
+
+// ResourceGasUsedError reports a resource gas used that differs from the usage of the block's transactions or receipts
+type ResourceGasUsedError struct {
+	Resource constants.Resource
+	Expected uint64
+	Got      uint64
+}
+
+func (e *ResourceGasUsedError) Error() string {
+	return fmt.Sprintf("%v: %s expected %d, got %d", ErrBadResourceGasUsed, e.Resource, e.Expected, e.Got)
+}
+
+func (e *ResourceGasUsedError) Unwrap() error { return ErrBadResourceGasUsed }
+
+// ResourceLimitError reports a block using more of a resource than its per-block limit
+type ResourceLimitError struct {
+	Resource constants.Resource
+	GasUsed  uint64
+	Limit    uint64
+}
+
+func (e *ResourceLimitError) Error() string {
+	return fmt.Sprintf("%v: %s used %d, limit %d", ErrResourceLimitExceeded, e.Resource, e.GasUsed, e.Limit)
+}
+
+func (e *ResourceLimitError) Unwrap() error { return ErrResourceLimitExceeded }
+
+// TimestampError reports a block timestamp that is not after its parent's
+type TimestampError struct {
+	Parent uint64
+	Got    uint64
+}
+
+func (e *TimestampError) Error() string {
+	return fmt.Sprintf("%v: parent %d, current %d", ErrBadTimestamp, e.Parent, e.Got)
+}
+
+func (e *TimestampError) Unwrap() error { return ErrBadTimestamp }
+
+// FutureBlockError reports a block timestamp more than AllowedFutureBlockTime ahead of the local clock
+type FutureBlockError struct {
+	Timestamp uint64
+	Now       uint64
+}
+
+func (e *FutureBlockError) Error() string {
+	return fmt.Sprintf("%v: timestamp %d, now %d", ErrFutureBlock, e.Timestamp, e.Now)
+}
+
+func (e *FutureBlockError) Unwrap() error { return ErrFutureBlock }
+
+// ExtraDataError reports a header carrying more than MaxExtraDataSize bytes of extra data
+type ExtraDataError struct {
+	Size int
+	Max  int
+}
+
+func (e *ExtraDataError) Error() string {
+	return fmt.Sprintf("%v: %d bytes, max %d", ErrExtraDataTooLong, e.Size, e.Max)
+}
+
+func (e *ExtraDataError) Unwrap() error { return ErrExtraDataTooLong }
+
+// TxCountError reports a header transaction count that differs from the number of transactions in the body
+type TxCountError struct {
+	Header uint64
+	Body   int
+}
+
+func (e *TxCountError) Error() string {
+	return fmt.Sprintf("%v: header %d, body %d", ErrBadTxCount, e.Header, e.Body)
+}
+
+func (e *TxCountError) Unwrap() error { return ErrBadTxCount }
+
+// ReceiptCountError reports execution results that do not cover every transaction of the block
+type ReceiptCountError struct {
+	Transactions int
+	Receipts     int
+}
+
+func (e *ReceiptCountError) Error() string {
+	return fmt.Sprintf("%v: %d transactions, %d receipts", ErrBadReceiptCount, e.Transactions, e.Receipts)
+}
+
+func (e *ReceiptCountError) Unwrap() error { return ErrBadReceiptCount }
+
+// GasUsedError reports a block gas used that differs from the gas used by its receipts
+type GasUsedError struct {
+	Expected uint64
+	Got      uint64
+}
+
+func (e *GasUsedError) Error() string {
+	return fmt.Sprintf("%v: expected %d, got %d", ErrBadGasUsed, e.Expected, e.Got)
+}
+
+func (e *GasUsedError) Unwrap() error { return ErrBadGasUsed }
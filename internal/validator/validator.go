@@ -3,109 +3,279 @@ package validator
 import (
 	"fmt"
 
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
 	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
 	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
 )
 
-// ValidateTransaction validates an EIP-1559 transaction
-func ValidateTransaction(tx *types.Transaction, baseFee uint64, state *types.State) error {
+// ValidateTransaction validates a transaction for inclusion in the block described by header
+// on the chain described by config. The header's base fee and excess blob gas set the fees
+// the transaction must cover.
+func ValidateTransaction(config *constants.ChainConfig, tx *types.Transaction, header *types.Block, state *types.State) error {
+	// Check the transaction was signed for this chain (EIP-155 replay protection)
+	if err := tx.ValidateChainID(config.ChainID); err != nil {
+		return err
+	}
+
 	// Basic transaction validation
-	if err := tx.Validate(baseFee); err != nil {
-		return fmt.Errorf("invalid transaction: %w", err)
+	if err := tx.Validate(header.BaseFee); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+
+	// Check blob transactions cover the blob base fee, or every resource's
+	// base fee when the block prices them separately
+	switch {
+	case header.IsMultidimensional():
+		if err := tx.ValidateResourceFees(header.ResourceBaseFees); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+		}
+	case tx.Type == types.ResourceFeeTxType:
+		return fmt.Errorf("%w: %w", ErrInvalidTransaction, types.ErrNotMultidimensional)
+	default:
+		if err := tx.ValidateBlobFee(basefee.CalculateBlobBaseFee(header.ExcessBlobGas)); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+		}
+	}
+
+	// Check the gas limit covers the intrinsic cost, including the access list
+	intrinsicGas, err := tx.IntrinsicGas()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	if tx.GasLimit < intrinsicGas {
+		return &types.IntrinsicGasError{GasLimit: tx.GasLimit, IntrinsicGas: intrinsicGas}
+	}
+
+	// Check the signature proves the claimed sender
+	sender, err := tx.Sender()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
+	if sender != tx.From {
+		return &types.SenderMismatchError{From: tx.From, Recovered: sender}
 	}
 
 	// Check sender has enough balance
-	sender := state.GetAccount(tx.From)
-	maxCost := tx.MaxCost()
+	account := state.GetAccount(sender)
+	maxCost, err := tx.MaxCost()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+	}
 
-	if !sender.CanPay(maxCost) {
-		return fmt.Errorf("insufficient funds: have %d, need %d", sender.Balance, maxCost)
+	if !account.CanPay(maxCost) {
+		return &types.InsufficientFundsError{Address: sender, Balance: account.Balance, Cost: maxCost}
 	}
 
 	// Check nonce
-	if tx.Nonce != sender.Nonce {
-		return fmt.Errorf("invalid nonce: have %d, expected %d", tx.Nonce, sender.Nonce)
+	if tx.Nonce != account.Nonce {
+		return &types.NonceError{Address: sender, TxNonce: tx.Nonce, StateNonce: account.Nonce}
 	}
 
 	return nil
 }
 
-// ValidateBlock validates a block according to EIP-1559 rules
-func ValidateBlock(block *types.Block, parent *types.Block) error {
+// ValidateBlock validates a block according to EIP-1559 rules under the chain's fee parameters.
+// It checks the header against its parent, its body and now, the local clock in Unix seconds,
+// which the timestamp may lead by at most AllowedFutureBlockTime. Once the block is executed,
+// use ValidateExecutedBlock, which also checks the receipts.
+func ValidateBlock(config *constants.ChainConfig, block *types.Block, parent *types.Block, now uint64) error {
+	return ValidateBlockWithRule(config, basefee.DefaultRule, block, parent, now)
+}
+
+// ValidateBlockWithRule validates a block like ValidateBlock, but expects the base fee set by rule
+func ValidateBlockWithRule(config *constants.ChainConfig, rule basefee.BaseFeeRule, block *types.Block, parent *types.Block, now uint64) error {
 	// Validate block number
 	if block.Number != parent.Number+1 {
-		return fmt.Errorf("invalid block number: expected %d, got %d", parent.Number+1, block.Number)
+		return &BlockNumberError{Expected: parent.Number + 1, Got: block.Number}
+	}
+
+	// Validate parent hash (derived from the parent's contents, so a tampered parent fails here)
+	if parentHash := parent.Hash(); block.ParentHash != parentHash {
+		return &ParentHashError{Expected: parentHash, Got: block.ParentHash}
+	}
+
+	// Validate timestamp is after the parent's
+	if block.Timestamp <= parent.Timestamp {
+		return &TimestampError{Parent: parent.Timestamp, Got: block.Timestamp}
+	}
+
+	// Validate timestamp is not too far ahead of the local clock
+	if err := ValidateBlockTime(block, now); err != nil {
+		return err
+	}
+
+	// Validate extra data size
+	if len(block.Extra) > constants.MaxExtraDataSize {
+		return &ExtraDataError{Size: len(block.Extra), Max: constants.MaxExtraDataSize}
+	}
+
+	// Validate the block names a fee recipient, so tips are not sent to the zero address
+	if block.Miner.IsZero() {
+		return ErrZeroMiner
 	}
 
-	// Validate parent hash
-	if block.ParentHash != parent.Hash {
-		return fmt.Errorf("invalid parent hash")
+	// Validate the header's transaction count against the body
+	if block.TxCount != uint64(len(block.Transactions)) {
+		return &TxCountError{Header: block.TxCount, Body: len(block.Transactions)}
 	}
 
-	// Validate gas used doesn't exceed gas limit
+	// Validate every transaction was signed for this chain
+	for i, tx := range block.Transactions {
+		if err := tx.ValidateChainID(config.ChainID); err != nil {
+			return fmt.Errorf("%w: tx %d: %w", ErrInvalidTransaction, i, err)
+		}
+	}
+
+	// Validate gas used doesn't exceed gas limit. Whether it matches execution is
+	// checked against the receipts by ValidateReceipts.
 	if block.GasUsed > block.GasLimit {
-		return fmt.Errorf("gas used (%d) exceeds gas limit (%d)", block.GasUsed, block.GasLimit)
+		return &types.GasLimitExceededError{GasUsed: block.GasUsed, GasLimit: block.GasLimit}
 	}
 
-	// Validate gas limit change (max 1/1024 change per block)
-	maxIncrease := parent.GasLimit + parent.GasLimit/constants.GasLimitBoundDivisor
-	maxDecrease := parent.GasLimit - parent.GasLimit/constants.GasLimitBoundDivisor
+	// Validate blob gas: within the per-block cap and equal to the blob gas of the transactions
+	if block.BlobGasUsed > constants.MaxBlobGasPerBlock {
+		return &types.BlobGasLimitExceededError{BlobGasUsed: block.BlobGasUsed, MaxBlobGas: constants.MaxBlobGasPerBlock}
+	}
+	var txBlobGas uint64
+	for _, tx := range block.Transactions {
+		var err error
+		if txBlobGas, err = types.SafeAddGas(txBlobGas, tx.BlobGas()); err != nil {
+			return err
+		}
+	}
+	if block.BlobGasUsed != txBlobGas {
+		return &BlobGasUsedError{Expected: txBlobGas, Got: block.BlobGasUsed}
+	}
 
-	if block.GasLimit > maxIncrease {
-		return fmt.Errorf("gas limit increased too much: parent %d, current %d, max %d",
-			parent.GasLimit, block.GasLimit, maxIncrease)
+	// Validate excess blob gas (carried forward from the parent)
+	expectedExcess, err := basefee.CalculateExcessBlobGas(parent)
+	if err != nil {
+		return err
+	}
+	if block.ExcessBlobGas != expectedExcess {
+		return &ExcessBlobGasError{Expected: expectedExcess, Got: block.ExcessBlobGas}
 	}
 
-	if block.GasLimit < maxDecrease {
-		return fmt.Errorf("gas limit decreased too much: parent %d, current %d, min %d",
-			parent.GasLimit, block.GasLimit, maxDecrease)
+	// Validate gas limit change (max 1/1024 change per block), never below the minimum.
+	// The fork block scales the parent limit by the elasticity multiplier so that
+	// the new target equals the old limit.
+	parentGasLimit := parent.GasLimit
+	if block.Number == config.LondonBlock {
+		if parentGasLimit, err = types.SafeMulGas(parent.GasLimit, config.ElasticityMultiplier); err != nil {
+			return &GasLimitError{ParentLimit: parent.GasLimit, Limit: block.GasLimit, Err: err}
+		}
+	}
+	maxLimit, err := types.SafeAddGas(parentGasLimit, parentGasLimit/constants.GasLimitBoundDivisor)
+	if err != nil {
+		return &GasLimitError{ParentLimit: parentGasLimit, Limit: block.GasLimit, Err: err}
+	}
+	minLimit := max(parentGasLimit-parentGasLimit/constants.GasLimitBoundDivisor, constants.MinGasLimit)
+
+	if block.GasLimit > maxLimit || block.GasLimit < minLimit {
+		return &GasLimitError{ParentLimit: parentGasLimit, Limit: block.GasLimit, Min: minLimit, Max: maxLimit}
 	}
 
-	// Validate minimum gas limit
-	if block.GasLimit < constants.MinGasLimit {
-		return fmt.Errorf("gas limit (%d) below minimum (%d)", block.GasLimit, constants.MinGasLimit)
+	// A multidimensional chain sets the base fee per resource, so the rule does not apply
+	if config.Resources != nil {
+		return validateResources(config, block, parent)
 	}
 
-	// Validate base fee (must match calculated value)
-	expectedBaseFee := calculateExpectedBaseFee(parent)
+	// Validate base fee (must match the value the rule derives from the parent;
+	// zero before the fork and the initial base fee on the fork block)
+	expectedBaseFee := basefee.NextBaseFee(rule, config, parent, block.Timestamp)
 	if block.BaseFee != expectedBaseFee {
-		return fmt.Errorf("invalid base fee: expected %d, got %d", expectedBaseFee, block.BaseFee)
+		return &BaseFeeError{Expected: expectedBaseFee, Got: block.BaseFee}
 	}
 
 	return nil
 }
 
-// calculateExpectedBaseFee calculates what the base fee should be
-func calculateExpectedBaseFee(parent *types.Block) uint64 {
-	// Import from basefee package to avoid duplication
-	// For now, inline the logic
-	parentGasTarget := parent.GasLimit / constants.ElasticityMultiplier
+// ValidateBlockTime checks that the block's timestamp is at most AllowedFutureBlockTime
+// seconds ahead of now, the local clock in Unix seconds
+func ValidateBlockTime(block *types.Block, now uint64) error {
+	if block.Timestamp > now && block.Timestamp-now > constants.AllowedFutureBlockTime {
+		return &FutureBlockError{Timestamp: block.Timestamp, Now: now}
+	}
+	return nil
+}
 
-	if parent.GasUsed == parentGasTarget {
-		return parent.BaseFee
+// ValidateExecutedBlock validates an executed block: its header and body like
+// ValidateBlockWithRule, then its gas used against the receipts of its transactions
+func ValidateExecutedBlock(config *constants.ChainConfig, rule basefee.BaseFeeRule, block, parent *types.Block, now uint64, receipts []*executor.ExecutionResult) error {
+	if err := ValidateBlockWithRule(config, rule, block, parent, now); err != nil {
+		return err
 	}
+	return ValidateReceipts(block, receipts)
+}
 
-	if parent.GasUsed > parentGasTarget {
-		gasUsedDelta := parent.GasUsed - parentGasTarget
-		baseFeePerGasDelta := max(
-			(parent.BaseFee*gasUsedDelta)/parentGasTarget/constants.BaseFeeChangeDenominator,
-			1,
-		)
-		return parent.BaseFee + baseFeePerGasDelta
+// ValidateReceipts checks the block against the results of executing its transactions:
+// one receipt per transaction, and GasUsed (and ResourceGasUsed on a multidimensional
+// block) equal to the gas of the receipts rather than the gas limits reserved by AddTransaction
+func ValidateReceipts(block *types.Block, receipts []*executor.ExecutionResult) error {
+	if len(receipts) != len(block.Transactions) {
+		return &ReceiptCountError{Transactions: len(block.Transactions), Receipts: len(receipts)}
 	}
 
-	gasUsedDelta := parentGasTarget - parent.GasUsed
-	baseFeePerGasDelta := (parent.BaseFee * gasUsedDelta) / parentGasTarget / constants.BaseFeeChangeDenominator
+	gasUsed, err := executor.TotalGasUsed(receipts)
+	if err != nil {
+		return err
+	}
+	if block.GasUsed != gasUsed {
+		return &GasUsedError{Expected: gasUsed, Got: block.GasUsed}
+	}
 
-	if baseFeePerGasDelta > parent.BaseFee {
-		return 0
+	if block.IsMultidimensional() {
+		resourceGasUsed, err := executor.TotalResourceGasUsed(receipts)
+		if err != nil {
+			return err
+		}
+		for r := range constants.NumResources {
+			if block.ResourceGasUsed[r] != resourceGasUsed[r] {
+				return &ResourceGasUsedError{Resource: constants.Resource(r), Expected: resourceGasUsed[r], Got: block.ResourceGasUsed[r]}
+			}
+		}
 	}
-	return parent.BaseFee - baseFeePerGasDelta
+	return nil
 }
 
-func max(a, b uint64) uint64 {
-	if a > b {
-		return a
+// validateResources checks the per-resource gas used and base fees of a block on a multidimensional chain
+func validateResources(config *constants.ChainConfig, block *types.Block, parent *types.Block) error {
+	// Validate resource base fees, each following the EIP-1559 rule against its own target
+	expectedFees := basefee.CalculateResourceBaseFees(config, parent)
+	for r := range constants.NumResources {
+		if block.ResourceBaseFees[r] != expectedFees[r] {
+			return &ResourceBaseFeeError{Resource: constants.Resource(r), Expected: expectedFees[r], Got: block.ResourceBaseFees[r]}
+		}
+	}
+	if expected := expectedFees[constants.ExecutionResource]; block.BaseFee != expected {
+		return &BaseFeeError{Expected: expected, Got: block.BaseFee}
 	}
-	return b
+
+	// Validate resource gas used: within each limit, and for calldata and blob gas,
+	// which the transactions fix, equal to their usage. Execution gas is checked
+	// against the receipts by ValidateReceipts. Blocks before the fork carry no
+	// resource accounting.
+	var txGas types.ResourceVector
+	if block.IsMultidimensional() {
+		for _, tx := range block.Transactions {
+			gas, err := tx.ResourceGasLimit()
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+			}
+			if txGas, err = txGas.Add(gas); err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
+			}
+		}
+	}
+	for r, params := range config.Resources {
+		if r != int(constants.ExecutionResource) && block.ResourceGasUsed[r] != txGas[r] {
+			return &ResourceGasUsedError{Resource: constants.Resource(r), Expected: txGas[r], Got: block.ResourceGasUsed[r]}
+		}
+		if block.ResourceGasUsed[r] > params.Limit {
+			return &ResourceLimitError{Resource: constants.Resource(r), GasUsed: block.ResourceGasUsed[r], Limit: params.Limit}
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,102 @@
+package constants
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// ErrInvalidChainConfig is returned for a chain config the fee market cannot run on
+var ErrInvalidChainConfig = errors.New("invalid chain config")
+
+// ChainConfig holds the fee market parameters that differ between EVM chains.
+// The package-level constants above are the Ethereum mainnet values; code that
+// should work across chains reads them from a ChainConfig instead.
+type ChainConfig struct {
+	Name    string
+	ChainID uint64
+
+	// LondonBlock is the first block with a base fee (EIP-1559 activation)
+	LondonBlock uint64
+
+	// InitialBaseFee is the base fee of the London block
+	InitialBaseFee uint256.Int
+
+	// BaseFeeChangeDenominator bounds the base fee change per block to 1/denominator
+	BaseFeeChangeDenominator uint64
+
+	// ElasticityMultiplier is the ratio of the gas limit to the gas target
+	ElasticityMultiplier uint64
+
+	// MinBaseFee and MaxBaseFee bound the base fee of the blocks after the London block; nil means unbounded
+	MinBaseFee *uint256.Int
+	MaxBaseFee *uint256.Int
+
+	// Resources enables the multidimensional fee market; nil prices all gas in one dimension.
+	// MinBaseFee and MaxBaseFee then bound the execution base fee only.
+	Resources *ResourceConfig
+}
+
+// IsLondon reports whether the block with the given number has a base fee
+func (c *ChainConfig) IsLondon(number uint64) bool {
+	return number >= c.LondonBlock
+}
+
+// Validate checks that the parameters are usable: non-zero denominator and
+// elasticity, a floor no higher than the ceiling, and complete resource parameters
+func (c *ChainConfig) Validate() error {
+	if c.BaseFeeChangeDenominator == 0 {
+		return fmt.Errorf("%w: zero base fee change denominator", ErrInvalidChainConfig)
+	}
+	if c.ElasticityMultiplier == 0 {
+		return fmt.Errorf("%w: zero elasticity multiplier", ErrInvalidChainConfig)
+	}
+	if c.MinBaseFee != nil && c.MaxBaseFee != nil && c.MinBaseFee.Gt(*c.MaxBaseFee) {
+		return fmt.Errorf("%w: base fee floor %s above ceiling %s", ErrInvalidChainConfig, c.MinBaseFee, c.MaxBaseFee)
+	}
+	if c.Resources != nil {
+		return c.Resources.Validate()
+	}
+	return nil
+}
+
+// Presets for well-known chains. They are shared values: copy one before changing a field.
+var (
+	// MainnetConfig is Ethereum mainnet
+	MainnetConfig = &ChainConfig{
+		Name:                     "mainnet",
+		ChainID:                  1,
+		LondonBlock:              ForkBlockNumber,
+		InitialBaseFee:           uint256.NewInt(InitialBaseFee),
+		BaseFeeChangeDenominator: BaseFeeChangeDenominator,
+		ElasticityMultiplier:     ElasticityMultiplier,
+	}
+
+	// OptimismConfig is OP Mainnet after the Canyon upgrade (denominator 250, elasticity 6)
+	OptimismConfig = &ChainConfig{
+		Name:                     "optimism",
+		ChainID:                  10,
+		LondonBlock:              105_235_063, // Bedrock
+		InitialBaseFee:           uint256.NewInt(InitialBaseFee),
+		BaseFeeChangeDenominator: 250,
+		ElasticityMultiplier:     6,
+	}
+
+	// BaseConfig is Base mainnet, an OP Stack chain with London active from genesis
+	BaseConfig = &ChainConfig{
+		Name:                     "base",
+		ChainID:                  8453,
+		LondonBlock:              0,
+		InitialBaseFee:           uint256.NewInt(InitialBaseFee),
+		BaseFeeChangeDenominator: 250,
+		ElasticityMultiplier:     6,
+	}
+)
+
+// ChainConfigs lists the presets by name
+var ChainConfigs = map[string]*ChainConfig{
+	MainnetConfig.Name:  MainnetConfig,
+	OptimismConfig.Name: OptimismConfig,
+	BaseConfig.Name:     BaseConfig,
+}
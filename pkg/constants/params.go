@@ -19,3 +19,63 @@ const (
 	// GasLimitBoundDivisor limits how much gas limit can change per block (1/1024)
 	GasLimitBoundDivisor uint64 = 1024
 )
+
+// Intrinsic gas costs charged before execution
+const (
+	// TxGas is the base cost of every transaction
+	TxGas uint64 = 21_000
+
+	// TxDataZeroGas is charged per zero calldata byte
+	TxDataZeroGas uint64 = 4
+
+	// TxDataNonZeroGas is charged per non-zero calldata byte
+	TxDataNonZeroGas uint64 = 16
+
+	// TxCreateGas is added for contract creation (53000 in total)
+	TxCreateGas uint64 = 32_000
+
+	// InitCodeWordGas is charged per 32-byte word of contract creation code (EIP-3860)
+	InitCodeWordGas uint64 = 2
+
+	// MaxInitCodeSize is the largest contract creation code allowed (EIP-3860)
+	MaxInitCodeSize = 2 * 24_576
+
+	// TxAccessListAddressGas is charged per address in an EIP-2930 access list
+	TxAccessListAddressGas uint64 = 2_400
+
+	// TxAccessListStorageKeyGas is charged per storage key in an EIP-2930 access list
+	TxAccessListStorageKeyGas uint64 = 1_900
+)
+
+// Header validity limits
+const (
+	// MaxExtraDataSize is the most extra data, in bytes, a header may carry
+	MaxExtraDataSize = 32
+
+	// AllowedFutureBlockTime is how many seconds a block timestamp may be ahead of the local clock
+	AllowedFutureBlockTime uint64 = 15
+)
+
+// SecondsPerSlot is the proof-of-stake slot time: one block every 12 seconds unless a slot is missed
+const SecondsPerSlot uint64 = 12
+
+// EIP-4844 blob gas parameters
+const (
+	// BlobGasPerBlob is the blob gas consumed by each blob (2^17)
+	BlobGasPerBlob uint64 = 1 << 17
+
+	// TargetBlobGasPerBlock is the blob gas a block aims to use (3 blobs)
+	TargetBlobGasPerBlock uint64 = 3 * BlobGasPerBlob
+
+	// MaxBlobGasPerBlock is the most blob gas a block may use (6 blobs)
+	MaxBlobGasPerBlock uint64 = 6 * BlobGasPerBlob
+
+	// MinBlobBaseFee is the lowest blob base fee (1 wei)
+	MinBlobBaseFee uint64 = 1
+
+	// BlobBaseFeeUpdateFraction controls how fast the blob base fee reacts to excess blob gas
+	BlobBaseFeeUpdateFraction uint64 = 3_338_477
+
+	// BlobHashVersionKZG is the version byte of a KZG commitment's versioned hash
+	BlobHashVersionKZG byte = 0x01
+)
@@ -0,0 +1,71 @@
+package constants
+
+import (
+	"fmt"
+
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// Resource is a dimension of the multidimensional fee market (EIP-7706 style),
+// each with its own target, limit and base fee
+type Resource int
+
+const (
+	ExecutionResource Resource = iota // EVM execution gas
+	CalldataResource                  // Calldata gas (4 per zero byte, 16 per non-zero byte)
+	BlobResource                      // EIP-4844 blob gas
+
+	// NumResources is the number of fee dimensions
+	NumResources = int(BlobResource) + 1
+)
+
+func (r Resource) String() string {
+	switch r {
+	case ExecutionResource:
+		return "execution"
+	case CalldataResource:
+		return "calldata"
+	case BlobResource:
+		return "blob"
+	}
+	return "unknown"
+}
+
+// ResourceParams are the per-block target and limit of one resource and its
+// base fee on the London block, all of which must be non-zero
+type ResourceParams struct {
+	Target         uint64
+	Limit          uint64
+	InitialBaseFee uint256.Int
+}
+
+// ResourceConfig holds the parameters of every resource, indexed by Resource
+type ResourceConfig [NumResources]ResourceParams
+
+// DefaultResourceConfig prices execution like mainnet, gives calldata a 1M target,
+// and keeps the EIP-4844 blob target and limit (updated by the EIP-1559 rule
+// instead of the blob fee's exponential)
+var DefaultResourceConfig = ResourceConfig{
+	ExecutionResource: {Target: 15_000_000, Limit: 30_000_000, InitialBaseFee: uint256.NewInt(InitialBaseFee)},
+	CalldataResource:  {Target: 1_000_000, Limit: 2_000_000, InitialBaseFee: uint256.NewInt(InitialBaseFee)},
+	BlobResource:      {Target: TargetBlobGasPerBlock, Limit: MaxBlobGasPerBlock, InitialBaseFee: uint256.NewInt(MinBlobBaseFee)},
+}
+
+// Validate rejects a resource with a zero target, limit or initial base fee (a zero
+// base fee never rises under the EIP-1559 rule) or a target above its limit
+func (c *ResourceConfig) Validate() error {
+	for r, params := range c {
+		resource := Resource(r)
+		switch {
+		case params.Target == 0:
+			return fmt.Errorf("%w: zero %s target", ErrInvalidChainConfig, resource)
+		case params.Limit == 0:
+			return fmt.Errorf("%w: zero %s limit", ErrInvalidChainConfig, resource)
+		case params.InitialBaseFee.IsZero():
+			return fmt.Errorf("%w: zero %s initial base fee", ErrInvalidChainConfig, resource)
+		case params.Target > params.Limit:
+			return fmt.Errorf("%w: %s target %d above limit %d", ErrInvalidChainConfig, resource, params.Target, params.Limit)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// Keccak-256 as used by Ethereum. This is the original Keccak submission
+// (padding byte 0x01), not the final NIST SHA3-256 (padding byte 0x06).
+
+const (
+	keccakRate = 136 // (1600 - 2*256) / 8
+	keccakSize = 32
+)
+
+var keccakRoundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotations[x][y] is the rho rotation offset for lane (x, y)
+var keccakRotations = [5][5]int{
+	{0, 36, 3, 41, 18},
+	{1, 44, 10, 45, 2},
+	{62, 6, 43, 15, 61},
+	{28, 55, 25, 21, 56},
+	{27, 20, 39, 8, 14},
+}
+
+// keccakF1600 applies the Keccak-f[1600] permutation to a, indexed a[x+5y]
+func keccakF1600(a *[25]uint64) {
+	var c, d [5]uint64
+	var b [25]uint64
+
+	for round := 0; round < 24; round++ {
+		// theta
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d[x] = c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+		}
+		for i := 0; i < 25; i++ {
+			a[i] ^= d[i%5]
+		}
+
+		// rho and pi
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				b[y+5*((2*x+3*y)%5)] = bits.RotateLeft64(a[x+5*y], keccakRotations[x][y])
+			}
+		}
+
+		// chi
+		for y := 0; y < 5; y++ {
+			for x := 0; x < 5; x++ {
+				a[x+5*y] = b[x+5*y] ^ (^b[(x+1)%5+5*y] & b[(x+2)%5+5*y])
+			}
+		}
+
+		// iota
+		a[0] ^= keccakRoundConstants[round]
+	}
+}
+
+// keccakState is a streaming Keccak-256 sponge implementing hash.Hash
+type keccakState struct {
+	a   [25]uint64
+	buf [keccakRate]byte
+	n   int // bytes buffered in buf
+}
+
+// NewKeccak256 returns a streaming Keccak-256 hasher
+func NewKeccak256() hash.Hash {
+	return &keccakState{}
+}
+
+func (s *keccakState) absorb(block []byte) {
+	for i := 0; i < keccakRate/8; i++ {
+		s.a[i] ^= binary.LittleEndian.Uint64(block[i*8:])
+	}
+	keccakF1600(&s.a)
+}
+
+func (s *keccakState) Write(p []byte) (int, error) {
+	written := len(p)
+
+	if s.n > 0 {
+		k := copy(s.buf[s.n:], p)
+		s.n += k
+		p = p[k:]
+		if s.n < keccakRate {
+			return written, nil
+		}
+		s.absorb(s.buf[:])
+		s.n = 0
+	}
+
+	for len(p) >= keccakRate {
+		s.absorb(p[:keccakRate])
+		p = p[keccakRate:]
+	}
+	s.n = copy(s.buf[:], p)
+
+	return written, nil
+}
+
+// Sum appends the digest to b without changing the hasher state
+func (s *keccakState) Sum(b []byte) []byte {
+	dup := *s
+
+	// pad10*1 with the Keccak domain byte
+	for i := dup.n; i < keccakRate; i++ {
+		dup.buf[i] = 0
+	}
+	dup.buf[dup.n] ^= 0x01
+	dup.buf[keccakRate-1] ^= 0x80
+	dup.absorb(dup.buf[:])
+
+	var out [keccakSize]byte
+	for i := 0; i < keccakSize/8; i++ {
+		binary.LittleEndian.PutUint64(out[i*8:], dup.a[i])
+	}
+	return append(b, out[:]...)
+}
+
+func (s *keccakState) Reset() {
+	*s = keccakState{}
+}
+
+func (s *keccakState) Size() int { return keccakSize }
+
+func (s *keccakState) BlockSize() int { return keccakRate }
+
+// Keccak256 returns the Keccak-256 digest of the concatenated inputs
+func Keccak256(data ...[]byte) []byte {
+	h := NewKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
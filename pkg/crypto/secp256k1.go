@@ -0,0 +1,356 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// secp256k1 ECDSA with public key recovery, as used for Ethereum transaction signatures.
+// Arithmetic uses math/big in affine coordinates; it favours clarity over speed and
+// makes no constant-time guarantees, so it is not suitable for protecting real funds.
+
+// SignatureLength is the size of a recoverable signature: R (32) || S (32) || V (1)
+const SignatureLength = 65
+
+var (
+	secp256k1P, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	secp256k1N, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	secp256k1Gx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	secp256k1Gy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+	secp256k1B     = big.NewInt(7)
+
+	// Secp256k1N is the order of the curve's base point
+	Secp256k1N = new(big.Int).Set(secp256k1N)
+
+	// Secp256k1HalfN is N/2, the largest S accepted under the EIP-2 low-s rule
+	Secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+var (
+	// ErrInvalidPrivateKey is returned for keys outside [1, N-1]
+	ErrInvalidPrivateKey = errors.New("invalid secp256k1 private key")
+
+	// ErrInvalidSignature is returned when a signature cannot be recovered
+	ErrInvalidSignature = errors.New("invalid secp256k1 signature")
+)
+
+// point is an affine curve point; a nil x marks the point at infinity
+type point struct {
+	x, y *big.Int
+}
+
+func (p point) isInfinity() bool {
+	return p.x == nil
+}
+
+// PublicKey is a secp256k1 public key
+type PublicKey struct {
+	X, Y *big.Int
+}
+
+// PrivateKey is a secp256k1 private key
+type PrivateKey struct {
+	PublicKey
+	D *big.Int
+}
+
+// GenerateKey creates a private key from crypto/rand
+func GenerateKey() (*PrivateKey, error) {
+	for {
+		var buf [32]byte
+		if _, err := rand.Read(buf[:]); err != nil {
+			return nil, err
+		}
+		if key, err := ToPrivateKey(buf[:]); err == nil {
+			return key, nil
+		}
+	}
+}
+
+// ToPrivateKey creates a private key from a 32-byte big-endian scalar
+func ToPrivateKey(b []byte) (*PrivateKey, error) {
+	if len(b) != 32 {
+		return nil, fmt.Errorf("%w: need 32 bytes, got %d", ErrInvalidPrivateKey, len(b))
+	}
+
+	d := new(big.Int).SetBytes(b)
+	if d.Sign() == 0 || d.Cmp(secp256k1N) >= 0 {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	pub := scalarBaseMult(d)
+	return &PrivateKey{PublicKey: PublicKey{X: pub.x, Y: pub.y}, D: d}, nil
+}
+
+// HexToPrivateKey parses a hex-encoded 32-byte private key
+func HexToPrivateKey(s string) (*PrivateKey, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrivateKey, err)
+	}
+	return ToPrivateKey(b)
+}
+
+// Bytes returns the 32-byte big-endian private scalar
+func (k *PrivateKey) Bytes() []byte {
+	return k.D.FillBytes(make([]byte, 32))
+}
+
+// Bytes returns the 65-byte uncompressed encoding 0x04 || X || Y
+func (p *PublicKey) Bytes() []byte {
+	out := make([]byte, 65)
+	out[0] = 0x04
+	p.X.FillBytes(out[1:33])
+	p.Y.FillBytes(out[33:])
+	return out
+}
+
+// Sign produces a recoverable signature R || S || V over a 32-byte hash.
+// The nonce is derived deterministically (RFC 6979) and S is normalised to the
+// lower half of the curve order, with V (0 or 1) adjusted to match.
+func Sign(hash []byte, key *PrivateKey) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("hash must be 32 bytes, got %d", len(hash))
+	}
+
+	z := hashToInt(hash)
+	nonces := newRFC6979(key.D, hash)
+
+	for {
+		k := nonces.next()
+		rp := scalarBaseMult(k)
+
+		r := new(big.Int).Mod(rp.x, secp256k1N)
+		if r.Sign() == 0 {
+			continue
+		}
+
+		// s = k^-1 (z + r*d) mod n
+		s := new(big.Int).Mul(r, key.D)
+		s.Add(s, z)
+		s.Mul(s, new(big.Int).ModInverse(k, secp256k1N))
+		s.Mod(s, secp256k1N)
+		if s.Sign() == 0 {
+			continue
+		}
+
+		v := byte(rp.y.Bit(0))
+		if rp.x.Cmp(secp256k1N) >= 0 {
+			v |= 2
+		}
+		if s.Cmp(Secp256k1HalfN) > 0 {
+			s.Sub(secp256k1N, s)
+			v ^= 1
+		}
+
+		sig := make([]byte, SignatureLength)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:64])
+		sig[64] = v
+		return sig, nil
+	}
+}
+
+// ValidateSignatureValues reports whether r and s are in range and v is a valid recovery id.
+// With lowS set, S above N/2 is rejected as malleable (EIP-2).
+func ValidateSignatureValues(v byte, r, s *big.Int, lowS bool) bool {
+	if r.Sign() <= 0 || s.Sign() <= 0 {
+		return false
+	}
+	if lowS && s.Cmp(Secp256k1HalfN) > 0 {
+		return false
+	}
+	return r.Cmp(secp256k1N) < 0 && s.Cmp(secp256k1N) < 0 && (v == 0 || v == 1)
+}
+
+// RecoverPubkey returns the public key that produced sig (R || S || V) over hash
+func RecoverPubkey(hash, sig []byte) (*PublicKey, error) {
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("%w: hash must be 32 bytes, got %d", ErrInvalidSignature, len(hash))
+	}
+	if len(sig) != SignatureLength {
+		return nil, fmt.Errorf("%w: need %d bytes, got %d", ErrInvalidSignature, SignatureLength, len(sig))
+	}
+
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	v := sig[64]
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(secp256k1N) >= 0 || s.Cmp(secp256k1N) >= 0 || v > 3 {
+		return nil, ErrInvalidSignature
+	}
+
+	// Reconstruct R from its x coordinate and the parity carried in v
+	x := new(big.Int).Set(r)
+	if v&2 != 0 {
+		x.Add(x, secp256k1N)
+		if x.Cmp(secp256k1P) >= 0 {
+			return nil, ErrInvalidSignature
+		}
+	}
+	y, ok := decompressY(x, v&1 == 1)
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+
+	// Q = r^-1 (s*R - z*G)
+	rInv := new(big.Int).ModInverse(r, secp256k1N)
+	negZ := new(big.Int).Sub(secp256k1N, new(big.Int).Mod(hashToInt(hash), secp256k1N))
+	q := add(scalarMult(point{x, y}, s), scalarBaseMult(negZ))
+	q = scalarMult(q, rInv)
+	if q.isInfinity() {
+		return nil, ErrInvalidSignature
+	}
+
+	return &PublicKey{X: q.x, Y: q.y}, nil
+}
+
+// hashToInt converts a 32-byte hash to an integer (bits2int for a 256-bit order)
+func hashToInt(hash []byte) *big.Int {
+	return new(big.Int).SetBytes(hash)
+}
+
+// decompressY returns the y coordinate for x with the requested parity
+func decompressY(x *big.Int, odd bool) (*big.Int, bool) {
+	// y^2 = x^3 + 7
+	y2 := new(big.Int).Exp(x, big.NewInt(3), secp256k1P)
+	y2.Add(y2, secp256k1B)
+	y2.Mod(y2, secp256k1P)
+
+	// p = 3 mod 4, so sqrt(a) = a^((p+1)/4)
+	exp := new(big.Int).Add(secp256k1P, big.NewInt(1))
+	exp.Rsh(exp, 2)
+	y := new(big.Int).Exp(y2, exp, secp256k1P)
+
+	if new(big.Int).Exp(y, big.NewInt(2), secp256k1P).Cmp(y2) != 0 {
+		return nil, false
+	}
+	if (y.Bit(0) == 1) != odd {
+		y.Sub(secp256k1P, y)
+	}
+	return y, true
+}
+
+func add(a, b point) point {
+	if a.isInfinity() {
+		return b
+	}
+	if b.isInfinity() {
+		return a
+	}
+
+	if a.x.Cmp(b.x) == 0 {
+		if a.y.Cmp(b.y) != 0 || a.y.Sign() == 0 {
+			return point{} // a == -b
+		}
+		return double(a)
+	}
+
+	// lambda = (by - ay) / (bx - ax)
+	num := new(big.Int).Sub(b.y, a.y)
+	den := new(big.Int).Sub(b.x, a.x)
+	den.Mod(den, secp256k1P)
+	lambda := num.Mul(num, den.ModInverse(den, secp256k1P))
+	lambda.Mod(lambda, secp256k1P)
+
+	return fromLambda(lambda, a, b.x)
+}
+
+func double(a point) point {
+	if a.isInfinity() || a.y.Sign() == 0 {
+		return point{}
+	}
+
+	// lambda = 3x^2 / 2y
+	num := new(big.Int).Mul(a.x, a.x)
+	num.Mul(num, big.NewInt(3))
+	den := new(big.Int).Lsh(a.y, 1)
+	den.Mod(den, secp256k1P)
+	lambda := num.Mul(num, den.ModInverse(den, secp256k1P))
+	lambda.Mod(lambda, secp256k1P)
+
+	return fromLambda(lambda, a, a.x)
+}
+
+// fromLambda completes point addition given the slope through a and a point with x coordinate bx
+func fromLambda(lambda *big.Int, a point, bx *big.Int) point {
+	// x3 = lambda^2 - ax - bx, y3 = lambda (ax - x3) - ay
+	x3 := new(big.Int).Mul(lambda, lambda)
+	x3.Sub(x3, a.x)
+	x3.Sub(x3, bx)
+	x3.Mod(x3, secp256k1P)
+
+	y3 := new(big.Int).Sub(a.x, x3)
+	y3.Mul(y3, lambda)
+	y3.Sub(y3, a.y)
+	y3.Mod(y3, secp256k1P)
+
+	return point{x3, y3}
+}
+
+func scalarMult(p point, k *big.Int) point {
+	result := point{}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = double(result)
+		if k.Bit(i) == 1 {
+			result = add(result, p)
+		}
+	}
+	return result
+}
+
+func scalarBaseMult(k *big.Int) point {
+	return scalarMult(point{secp256k1Gx, secp256k1Gy}, k)
+}
+
+// rfc6979 generates deterministic ECDSA nonces with HMAC-SHA256 (RFC 6979, section 3.2)
+type rfc6979 struct {
+	k, v []byte
+	init bool
+}
+
+func newRFC6979(d *big.Int, hash []byte) *rfc6979 {
+	x := d.FillBytes(make([]byte, 32))
+	h := new(big.Int).Mod(hashToInt(hash), secp256k1N).FillBytes(make([]byte, 32))
+
+	g := &rfc6979{k: make([]byte, 32), v: make([]byte, 32)}
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+
+	g.k = g.mac(g.v, []byte{0x00}, x, h)
+	g.v = g.mac(g.v)
+	g.k = g.mac(g.v, []byte{0x01}, x, h)
+	g.v = g.mac(g.v)
+	return g
+}
+
+func (g *rfc6979) mac(data ...[]byte) []byte {
+	m := hmac.New(sha256.New, g.k)
+	for _, d := range data {
+		m.Write(d)
+	}
+	return m.Sum(nil)
+}
+
+// next returns the next candidate nonce in [1, N-1]
+func (g *rfc6979) next() *big.Int {
+	for {
+		if g.init {
+			g.k = g.mac(g.v, []byte{0x00})
+			g.v = g.mac(g.v)
+		}
+		g.init = true
+
+		g.v = g.mac(g.v)
+		k := new(big.Int).SetBytes(g.v)
+		if k.Sign() > 0 && k.Cmp(secp256k1N) < 0 {
+			return k
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package hexutil
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// Hex encodings used by the Ethereum JSON-RPC API. Quantities are 0x-prefixed,
+// big-endian and without leading zeros ("0x0", "0x5208"); data is 0x-prefixed
+// with two hex characters per byte ("0x", "0xcafe").
+
+var (
+	ErrMissingPrefix = errors.New("hex string without 0x prefix")
+	ErrEmptyNumber   = errors.New("hex string \"0x\"")
+	ErrLeadingZero   = errors.New("hex number with leading zero digits")
+	ErrOddLength     = errors.New("hex string of odd length")
+	ErrSyntax        = errors.New("invalid hex string")
+	ErrUint64Range   = errors.New("hex number > 64 bits")
+	ErrUint256Range  = errors.New("hex number > 256 bits")
+)
+
+// Uint64 marshals as a JSON-RPC quantity
+type Uint64 uint64
+
+// MarshalText implements encoding.TextMarshaler
+func (n Uint64) MarshalText() ([]byte, error) {
+	return []byte(EncodeUint64(uint64(n))), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (n *Uint64) UnmarshalText(text []byte) error {
+	v, err := DecodeUint64(string(text))
+	if err != nil {
+		return err
+	}
+	*n = Uint64(v)
+	return nil
+}
+
+// U256 marshals a uint256.Int as a JSON-RPC quantity
+type U256 uint256.Int
+
+// MarshalText implements encoding.TextMarshaler
+func (n U256) MarshalText() ([]byte, error) {
+	return []byte(EncodeUint256(uint256.Int(n))), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (n *U256) UnmarshalText(text []byte) error {
+	v, err := DecodeUint256(string(text))
+	if err != nil {
+		return err
+	}
+	*n = U256(v)
+	return nil
+}
+
+// Bytes marshals as JSON-RPC data
+type Bytes []byte
+
+// MarshalText implements encoding.TextMarshaler
+func (b Bytes) MarshalText() ([]byte, error) {
+	return []byte(Encode(b)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (b *Bytes) UnmarshalText(text []byte) error {
+	dec, err := Decode(string(text))
+	if err != nil {
+		return err
+	}
+	*b = dec
+	return nil
+}
+
+// Encode returns b as 0x-prefixed hex data
+func Encode(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+// Decode parses 0x-prefixed hex data
+func Decode(s string) ([]byte, error) {
+	raw, err := trimPrefix(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw)%2 != 0 {
+		return nil, fmt.Errorf("%w: %q", ErrOddLength, s)
+	}
+	b, err := hex.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrSyntax, s)
+	}
+	return b, nil
+}
+
+// EncodeUint64 returns n as a quantity
+func EncodeUint64(n uint64) string {
+	return "0x" + strconv.FormatUint(n, 16)
+}
+
+// DecodeUint64 parses a quantity that fits in 64 bits
+func DecodeUint64(s string) (uint64, error) {
+	raw, err := quantityDigits(s)
+	if err != nil {
+		return 0, err
+	}
+	if len(raw) > 16 {
+		return 0, fmt.Errorf("%w: %q", ErrUint64Range, s)
+	}
+	n, err := strconv.ParseUint(raw, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrSyntax, s)
+	}
+	return n, nil
+}
+
+// EncodeUint256 returns n as a quantity
+func EncodeUint256(n uint256.Int) string {
+	return "0x" + n.ToBig().Text(16)
+}
+
+// DecodeUint256 parses a quantity that fits in 256 bits
+func DecodeUint256(s string) (uint256.Int, error) {
+	raw, err := quantityDigits(s)
+	if err != nil {
+		return uint256.Zero, err
+	}
+	if len(raw) > 64 {
+		return uint256.Zero, fmt.Errorf("%w: %q", ErrUint256Range, s)
+	}
+	if len(raw)%2 != 0 {
+		raw = "0" + raw
+	}
+	b, err := hex.DecodeString(raw)
+	if err != nil {
+		return uint256.Zero, fmt.Errorf("%w: %q", ErrSyntax, s)
+	}
+	return uint256.FromBytes(b), nil
+}
+
+// quantityDigits strips the prefix of a quantity and rejects empty or zero-padded numbers
+func quantityDigits(s string) (string, error) {
+	raw, err := trimPrefix(s)
+	if err != nil {
+		return "", err
+	}
+	if raw == "" {
+		return "", ErrEmptyNumber
+	}
+	if len(raw) > 1 && raw[0] == '0' {
+		return "", fmt.Errorf("%w: %q", ErrLeadingZero, s)
+	}
+	return raw, nil
+}
+
+func trimPrefix(s string) (string, error) {
+	if len(s) < 2 || s[0] != '0' || (s[1] != 'x' && s[1] != 'X') {
+		return "", fmt.Errorf("%w: %q", ErrMissingPrefix, s)
+	}
+	return s[2:], nil
+}
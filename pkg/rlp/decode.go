@@ -0,0 +1,176 @@
+package rlp
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnexpectedEnd is returned when input ends before a value is complete
+	ErrUnexpectedEnd = errors.New("rlp: unexpected end of input")
+
+	// ErrExpectedString is returned when a list is found where a string is required
+	ErrExpectedString = errors.New("rlp: expected string")
+
+	// ErrExpectedList is returned when a string is found where a list is required
+	ErrExpectedList = errors.New("rlp: expected list")
+
+	// ErrCanonSize is returned for size prefixes that are not minimal
+	ErrCanonSize = errors.New("rlp: non-canonical size information")
+
+	// ErrCanonInt is returned for integers with leading zero bytes
+	ErrCanonInt = errors.New("rlp: non-canonical integer (leading zero bytes)")
+
+	// ErrUint64Range is returned for integers that do not fit in a uint64
+	ErrUint64Range = errors.New("rlp: integer too large for uint64")
+
+	// ErrTrailingData is returned when bytes remain after a complete value
+	ErrTrailingData = errors.New("rlp: trailing data after value")
+)
+
+// Kind is the type of an RLP value
+type Kind int
+
+const (
+	Byte Kind = iota // single byte below 0x80, encoded as itself
+	String
+	List
+)
+
+// Split reads the first RLP value in b and returns its kind, its payload and the remaining bytes
+func Split(b []byte) (k Kind, content, rest []byte, err error) {
+	if len(b) == 0 {
+		return 0, nil, nil, ErrUnexpectedEnd
+	}
+
+	prefix := b[0]
+	var offset, size uint64
+
+	switch {
+	case prefix < shortStringOffset:
+		return Byte, b[:1], b[1:], nil
+
+	case prefix <= longStringOffset:
+		k, offset, size = String, 1, uint64(prefix-shortStringOffset)
+		// A single byte below 0x80 must be encoded as itself
+		if size == 1 && len(b) > 1 && b[1] < shortStringOffset {
+			return 0, nil, nil, ErrCanonSize
+		}
+
+	case prefix < shortListOffset:
+		k = String
+		offset, size, err = readLongSize(b, prefix-longStringOffset)
+
+	case prefix <= longListOffset:
+		k, offset, size = List, 1, uint64(prefix-shortListOffset)
+
+	default:
+		k = List
+		offset, size, err = readLongSize(b, prefix-longListOffset)
+	}
+
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if size > uint64(len(b))-offset {
+		return 0, nil, nil, ErrUnexpectedEnd
+	}
+
+	return k, b[offset : offset+size], b[offset+size:], nil
+}
+
+// readLongSize decodes the big-endian size that follows a long-form prefix
+func readLongSize(b []byte, sizeLen byte) (offset, size uint64, err error) {
+	if uint64(len(b)) < 1+uint64(sizeLen) {
+		return 0, 0, ErrUnexpectedEnd
+	}
+	if b[1] == 0 {
+		return 0, 0, ErrCanonSize
+	}
+	if sizeLen > 8 {
+		return 0, 0, fmt.Errorf("%w: %d-byte size", ErrCanonSize, sizeLen)
+	}
+
+	for _, c := range b[1 : 1+sizeLen] {
+		size = size<<8 | uint64(c)
+	}
+	// Payloads of 55 bytes or fewer must use the short form
+	if size <= shortMaxLength {
+		return 0, 0, ErrCanonSize
+	}
+	return 1 + uint64(sizeLen), size, nil
+}
+
+// SplitString reads the first value in b, which must be a string (or single byte)
+func SplitString(b []byte) (content, rest []byte, err error) {
+	k, content, rest, err := Split(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if k == List {
+		return nil, nil, ErrExpectedString
+	}
+	return content, rest, nil
+}
+
+// SplitList reads the first value in b, which must be a list, and returns its payload
+func SplitList(b []byte) (content, rest []byte, err error) {
+	k, content, rest, err := Split(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if k != List {
+		return nil, nil, ErrExpectedList
+	}
+	return content, rest, nil
+}
+
+// SplitInt reads the first value in b as a canonical big-endian integer
+func SplitInt(b []byte) (content, rest []byte, err error) {
+	content, rest, err = SplitString(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(content) > 0 && content[0] == 0 {
+		return nil, nil, ErrCanonInt
+	}
+	return content, rest, nil
+}
+
+// SplitUint64 reads the first value in b as a uint64
+func SplitUint64(b []byte) (x uint64, rest []byte, err error) {
+	content, rest, err := SplitInt(b)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(content) > 8 {
+		return 0, nil, ErrUint64Range
+	}
+
+	for _, c := range content {
+		x = x<<8 | uint64(c)
+	}
+	return x, rest, nil
+}
+
+// ListElements splits b, which must hold exactly one list, into its raw encoded elements
+func ListElements(b []byte) ([][]byte, error) {
+	content, rest, err := SplitList(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, ErrTrailingData
+	}
+
+	var elems [][]byte
+	for len(content) > 0 {
+		_, _, next, err := Split(content)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, content[:len(content)-len(next)])
+		content = next
+	}
+	return elems, nil
+}
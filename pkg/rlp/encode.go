@@ -0,0 +1,68 @@
+package rlp
+
+// Recursive Length Prefix encoding as defined in the Ethereum Yellow Paper, appendix B.
+// Values are encoded bottom-up: encode each item, then wrap the items with EncodeList.
+
+const (
+	shortStringOffset = 0x80
+	longStringOffset  = 0xb7
+	shortListOffset   = 0xc0
+	longListOffset    = 0xf7
+	shortMaxLength    = 55
+)
+
+// EmptyString is the encoding of an empty byte string (also the encoding of integer zero)
+var EmptyString = []byte{shortStringOffset}
+
+// EmptyList is the encoding of an empty list
+var EmptyList = []byte{shortListOffset}
+
+// EncodeBytes encodes b as an RLP string
+func EncodeBytes(b []byte) []byte {
+	if len(b) == 1 && b[0] < shortStringOffset {
+		return []byte{b[0]}
+	}
+	return append(encodeHeader(len(b), shortStringOffset, longStringOffset), b...)
+}
+
+// EncodeString encodes s as an RLP string
+func EncodeString(s string) []byte {
+	return EncodeBytes([]byte(s))
+}
+
+// EncodeUint64 encodes u as an RLP integer: its minimal big-endian bytes
+func EncodeUint64(u uint64) []byte {
+	return EncodeBytes(uintBytes(u))
+}
+
+// EncodeList wraps already-encoded items into an RLP list
+func EncodeList(items ...[]byte) []byte {
+	size := 0
+	for _, item := range items {
+		size += len(item)
+	}
+
+	out := encodeHeader(size, shortListOffset, longListOffset)
+	for _, item := range items {
+		out = append(out, item...)
+	}
+	return out
+}
+
+// encodeHeader returns the prefix for a payload of the given size
+func encodeHeader(size int, shortOffset, longOffset byte) []byte {
+	if size <= shortMaxLength {
+		return []byte{shortOffset + byte(size)}
+	}
+	sizeBytes := uintBytes(uint64(size))
+	return append([]byte{longOffset + byte(len(sizeBytes))}, sizeBytes...)
+}
+
+// uintBytes returns the minimal big-endian representation of u (empty for zero)
+func uintBytes(u uint64) []byte {
+	var b []byte
+	for ; u > 0; u >>= 8 {
+		b = append([]byte{byte(u)}, b...)
+	}
+	return b
+}
@@ -0,0 +1,306 @@
+package uint256
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// Int is an unsigned 256-bit integer used for wei-denominated quantities.
+// Limbs are stored little-endian: Int[0] holds the least significant 64 bits.
+// Int is a value type, so copies never alias and == compares numerically.
+type Int [4]uint64
+
+var (
+	// ErrOverflow is returned when a value does not fit in 256 bits
+	ErrOverflow = errors.New("uint256: value overflows 256 bits")
+
+	// ErrSyntax is returned when a string cannot be parsed as a number
+	ErrSyntax = errors.New("uint256: invalid syntax")
+)
+
+// Zero is the zero value, spelled out for readability at call sites
+var Zero = Int{}
+
+// Max is the largest representable value, 2^256 - 1
+var Max = Int{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}
+
+// NewInt returns an Int holding v
+func NewInt(v uint64) Int {
+	return Int{v, 0, 0, 0}
+}
+
+// FromBig converts a non-negative big.Int, reporting whether it overflowed
+func FromBig(b *big.Int) (Int, bool) {
+	if b.Sign() < 0 || b.BitLen() > 256 {
+		return Int{}, true
+	}
+
+	var z Int
+	words := b.Bits()
+	if bits.UintSize == 64 {
+		for i := 0; i < len(words) && i < 4; i++ {
+			z[i] = uint64(words[i])
+		}
+		return z, false
+	}
+
+	// 32-bit platforms: pack pairs of words into each limb
+	for i := 0; i < len(words) && i < 8; i++ {
+		z[i/2] |= uint64(words[i]) << (32 * uint(i%2))
+	}
+	return z, false
+}
+
+// MustFromBig is like FromBig but panics on overflow
+func MustFromBig(b *big.Int) Int {
+	z, overflow := FromBig(b)
+	if overflow {
+		panic(ErrOverflow)
+	}
+	return z
+}
+
+// FromDecimal parses a base-10 string
+func FromDecimal(s string) (Int, error) {
+	b, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return Int{}, fmt.Errorf("%w: %q", ErrSyntax, s)
+	}
+	z, overflow := FromBig(b)
+	if overflow {
+		return Int{}, ErrOverflow
+	}
+	return z, nil
+}
+
+// MustFromDecimal is like FromDecimal but panics on error
+func MustFromDecimal(s string) Int {
+	z, err := FromDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return z
+}
+
+// FromBytes interprets b as a big-endian integer. Only the last 32 bytes are used.
+func FromBytes(b []byte) Int {
+	if len(b) > 32 {
+		b = b[len(b)-32:]
+	}
+
+	var z Int
+	for i, v := range b {
+		shift := uint(len(b)-1-i) * 8
+		z[shift/64] |= uint64(v) << (shift % 64)
+	}
+	return z
+}
+
+// ToBig returns x as a new big.Int
+func (x Int) ToBig() *big.Int {
+	var buf [32]byte
+	b := x.Bytes32()
+	copy(buf[:], b[:])
+	return new(big.Int).SetBytes(buf[:])
+}
+
+// Bytes32 returns x as a 32-byte big-endian array
+func (x Int) Bytes32() [32]byte {
+	var b [32]byte
+	for i := 0; i < 4; i++ {
+		limb := x[3-i]
+		for j := 0; j < 8; j++ {
+			b[i*8+j] = byte(limb >> (56 - 8*uint(j)))
+		}
+	}
+	return b
+}
+
+// Bytes returns the minimal big-endian encoding of x (empty for zero)
+func (x Int) Bytes() []byte {
+	b := x.Bytes32()
+	i := 0
+	for i < len(b) && b[i] == 0 {
+		i++
+	}
+	return append([]byte(nil), b[i:]...)
+}
+
+// IsZero reports whether x == 0
+func (x Int) IsZero() bool {
+	return x == Int{}
+}
+
+// IsUint64 reports whether x fits in a uint64
+func (x Int) IsUint64() bool {
+	return x[1]|x[2]|x[3] == 0
+}
+
+// Uint64 returns the low 64 bits of x
+func (x Int) Uint64() uint64 {
+	return x[0]
+}
+
+// Float64 returns the nearest float64 to x
+func (x Int) Float64() float64 {
+	f, _ := new(big.Float).SetInt(x.ToBig()).Float64()
+	return f
+}
+
+// BitLen returns the number of bits required to represent x
+func (x Int) BitLen() int {
+	for i := 3; i >= 0; i-- {
+		if x[i] != 0 {
+			return i*64 + bits.Len64(x[i])
+		}
+	}
+	return 0
+}
+
+// Cmp returns -1, 0 or +1 depending on whether x is less than, equal to or greater than y
+func (x Int) Cmp(y Int) int {
+	for i := 3; i >= 0; i-- {
+		if x[i] < y[i] {
+			return -1
+		}
+		if x[i] > y[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Lt reports whether x < y
+func (x Int) Lt(y Int) bool {
+	return x.Cmp(y) < 0
+}
+
+// Gt reports whether x > y
+func (x Int) Gt(y Int) bool {
+	return x.Cmp(y) > 0
+}
+
+// AddOverflow returns x + y and whether the addition overflowed
+func (x Int) AddOverflow(y Int) (Int, bool) {
+	var z Int
+	var carry uint64
+	z[0], carry = bits.Add64(x[0], y[0], 0)
+	z[1], carry = bits.Add64(x[1], y[1], carry)
+	z[2], carry = bits.Add64(x[2], y[2], carry)
+	z[3], carry = bits.Add64(x[3], y[3], carry)
+	return z, carry != 0
+}
+
+// Add returns x + y modulo 2^256
+func (x Int) Add(y Int) Int {
+	z, _ := x.AddOverflow(y)
+	return z
+}
+
+// SubUnderflow returns x - y and whether the subtraction underflowed
+func (x Int) SubUnderflow(y Int) (Int, bool) {
+	var z Int
+	var borrow uint64
+	z[0], borrow = bits.Sub64(x[0], y[0], 0)
+	z[1], borrow = bits.Sub64(x[1], y[1], borrow)
+	z[2], borrow = bits.Sub64(x[2], y[2], borrow)
+	z[3], borrow = bits.Sub64(x[3], y[3], borrow)
+	return z, borrow != 0
+}
+
+// Sub returns x - y modulo 2^256
+func (x Int) Sub(y Int) Int {
+	z, _ := x.SubUnderflow(y)
+	return z
+}
+
+// MulOverflow returns x * y truncated to 256 bits and whether the product overflowed
+func (x Int) MulOverflow(y Int) (Int, bool) {
+	// Schoolbook multiplication into a 512-bit accumulator
+	var acc [8]uint64
+	for i := 0; i < 4; i++ {
+		if x[i] == 0 {
+			continue
+		}
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			var c uint64
+			lo, c = bits.Add64(lo, acc[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			acc[i+j] = lo
+			carry = hi
+		}
+		acc[i+4] = carry
+	}
+
+	z := Int{acc[0], acc[1], acc[2], acc[3]}
+	return z, acc[4]|acc[5]|acc[6]|acc[7] != 0
+}
+
+// Mul returns x * y modulo 2^256
+func (x Int) Mul(y Int) Int {
+	z, _ := x.MulOverflow(y)
+	return z
+}
+
+// Div returns x / y, or 0 if y == 0 (matching EVM semantics)
+func (x Int) Div(y Int) Int {
+	if y.IsZero() || x.Lt(y) {
+		return Int{}
+	}
+	if x.IsUint64() {
+		return NewInt(x[0] / y[0])
+	}
+	return MustFromBig(new(big.Int).Quo(x.ToBig(), y.ToBig()))
+}
+
+// Mod returns x % y, or 0 if y == 0 (matching EVM semantics)
+func (x Int) Mod(y Int) Int {
+	if y.IsZero() {
+		return Int{}
+	}
+	if x.Lt(y) {
+		return x
+	}
+	if x.IsUint64() {
+		return NewInt(x[0] % y[0])
+	}
+	return MustFromBig(new(big.Int).Rem(x.ToBig(), y.ToBig()))
+}
+
+// String returns the base-10 representation of x
+func (x Int) String() string {
+	if x.IsUint64() {
+		return fmt.Sprintf("%d", x[0])
+	}
+	return x.ToBig().String()
+}
+
+// Format implements fmt.Formatter so Int works with %d, %x, %s and %v verbs
+func (x Int) Format(s fmt.State, ch rune) {
+	if ch == 's' || ch == 'v' {
+		ch = 'd'
+	}
+	x.ToBig().Format(s, ch)
+}
+
+// MaxOf returns the larger of a and b
+func MaxOf(a, b Int) Int {
+	if a.Gt(b) {
+		return a
+	}
+	return b
+}
+
+// MinOf returns the smaller of a and b
+func MinOf(a, b Int) Int {
+	if a.Lt(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,180 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/rlp"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+var testAccessList = types.AccessList{
+	{
+		Address: carol,
+		StorageKeys: []types.Hash{
+			types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001"),
+			types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002"),
+		},
+	},
+	{
+		Address: bob,
+		StorageKeys: []types.Hash{
+			types.HexToHash("0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563"),
+		},
+	},
+}
+
+func TestAccessListIntrinsicGas(t *testing.T) {
+	gas, err := testAccessList.IntrinsicGas()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := uint64(2*2_400 + 3*1_900); gas != expected {
+		t.Errorf("expected %d, got %d", expected, gas)
+	}
+
+	tx := &types.Transaction{To: &carol, Data: []byte{1, 2, 3}, AccessList: testAccessList}
+	gas, err = tx.IntrinsicGas()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := uint64(21_000 + 3*16 + 2*2_400 + 3*1_900); gas != expected {
+		t.Errorf("expected %d, got %d", expected, gas)
+	}
+}
+
+func TestExecuteChargesAccessListGas(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+
+	block := types.NewBlock(1, types.Hash{}, 30_000_000, uint256.NewInt(1_000_000_000), miner)
+	tx := signTx(t, &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		ChainID:              1,
+		MaxPriorityFeePerGas: uint256.NewInt(1_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(2_000_000_000),
+		GasLimit:             50_000,
+		To:                   &bob,
+		AccessList:           testAccessList,
+	}, aliceKey)
+
+	if err := validator.ValidateTransaction(constants.MainnetConfig, tx, block, state); err != nil {
+		t.Fatalf("validation failed: %v", err)
+	}
+
+	result := executor.ExecuteTransaction(tx, block, state)
+	if !result.Success {
+		t.Fatalf("execution failed: %v", result.Error)
+	}
+	if expected := uint64(21_000 + 2*2_400 + 3*1_900); result.GasUsed != expected {
+		t.Errorf("expected gas used %d, got %d", expected, result.GasUsed)
+	}
+}
+
+func TestValidateRejectsGasBelowAccessListCost(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+
+	tx := signTx(t, &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		ChainID:              1,
+		MaxPriorityFeePerGas: uint256.NewInt(1_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(2_000_000_000),
+		GasLimit:             21_000,
+		To:                   &bob,
+		AccessList:           testAccessList,
+	}, aliceKey)
+
+	err := validator.ValidateTransaction(constants.MainnetConfig, tx, &types.Block{BaseFee: uint256.NewInt(1_000_000_000)}, state)
+	var gasErr *types.IntrinsicGasError
+	if !errors.As(err, &gasErr) {
+		t.Fatalf("expected IntrinsicGasError, got %v", err)
+	}
+	if gasErr.IntrinsicGas != 21_000+2*2_400+3*1_900 || gasErr.GasLimit != 21_000 {
+		t.Errorf("unexpected values: %+v", gasErr)
+	}
+}
+
+func TestAccessListEncoding(t *testing.T) {
+	tx := signTx(t, &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		ChainID:              1,
+		MaxPriorityFeePerGas: uint256.NewInt(1),
+		MaxFeePerGas:         uint256.NewInt(2),
+		GasLimit:             50_000,
+		To:                   &bob,
+		AccessList:           testAccessList,
+	}, aliceKey)
+
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	dec, err := types.DecodeTransaction(enc)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(dec.AccessList, tx.AccessList) {
+		t.Errorf("access list mismatch:\n got %+v\nwant %+v", dec.AccessList, tx.AccessList)
+	}
+
+	// The access list is covered by the signature
+	dec.From = alice
+	if sender, err := dec.Sender(); err != nil || sender != alice {
+		t.Fatalf("expected sender %s, got %s (%v)", alice, sender, err)
+	}
+	dec.AccessList = dec.AccessList[:1]
+	if sender, _ := dec.Sender(); sender == alice {
+		t.Error("dropping an access list entry should change the recovered sender")
+	}
+}
+
+func TestDecodeMalformedAccessList(t *testing.T) {
+	valid, _ := (&types.Transaction{Type: types.DynamicFeeTxType, ChainID: 1, GasLimit: 21_000, To: &bob}).MarshalBinary()
+	withAccessList := func(al []byte) []byte {
+		elems, _ := rlp.ListElements(valid[1:])
+		elems[8] = al
+		return append([]byte{types.DynamicFeeTxType}, rlp.EncodeList(elems...)...)
+	}
+
+	tests := []struct {
+		name  string
+		al    []byte
+		errIs error
+	}{
+		{
+			name:  "tuple is not a list",
+			al:    rlp.EncodeList(rlp.EncodeString("x")),
+			errIs: rlp.ErrExpectedList,
+		},
+		{
+			name:  "short address",
+			al:    rlp.EncodeList(rlp.EncodeList(rlp.EncodeBytes([]byte{1}), rlp.EmptyList)),
+			errIs: types.ErrInvalidAddress,
+		},
+		{
+			name:  "short storage key",
+			al:    rlp.EncodeList(rlp.EncodeList(rlp.EncodeBytes(bob[:]), rlp.EncodeList(rlp.EncodeBytes([]byte{1})))),
+			errIs: types.ErrInvalidHash,
+		},
+		{
+			name:  "missing storage keys",
+			al:    rlp.EncodeList(rlp.EncodeList(rlp.EncodeBytes(bob[:]))),
+			errIs: types.ErrMalformedTransaction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := types.DecodeTransaction(withAccessList(tt.al)); !errors.Is(err, tt.errIs) {
+				t.Errorf("expected %v, got %v", tt.errIs, err)
+			}
+		})
+	}
+}
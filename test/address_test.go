@@ -0,0 +1,153 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// Checksummed addresses from the EIP-55 specification
+var eip55Vectors = []string{
+	"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+	"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+	"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+}
+
+func TestAddressChecksum(t *testing.T) {
+	for _, want := range eip55Vectors {
+		t.Run(want, func(t *testing.T) {
+			for _, input := range []string{want, strings.ToLower(want), "0x" + strings.ToUpper(want[2:])} {
+				addr, err := types.ParseAddress(input)
+				if err != nil {
+					t.Fatalf("parse %s: %v", input, err)
+				}
+				if addr.Hex() != want {
+					t.Errorf("expected %s, got %s", want, addr.Hex())
+				}
+			}
+		})
+	}
+}
+
+func TestParseAddressErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		errIs error
+	}{
+		{name: "bad checksum", input: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", errIs: types.ErrAddressChecksum},
+		{name: "too short", input: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", errIs: types.ErrInvalidAddress},
+		{name: "not hex", input: "0xAlice", errIs: types.ErrInvalidAddress},
+		{name: "non-hex characters", input: "0xzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz", errIs: types.ErrInvalidAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := types.ParseAddress(tt.input); !errors.Is(err, tt.errIs) {
+				t.Errorf("expected %v, got %v", tt.errIs, err)
+			}
+		})
+	}
+}
+
+func TestAddressAndHashJSON(t *testing.T) {
+	addr := types.HexToAddress(eip55Vectors[0])
+	hash := types.HexToHash("0x9b83c12c69edb74f6c8dd5d052765c1adf940e320bd1291696e6fa07829eee71")
+
+	in := struct {
+		Addr     types.Address
+		Hash     types.Hash
+		Balances map[types.Address]string
+	}{
+		Addr:     addr,
+		Hash:     hash,
+		Balances: map[types.Address]string{addr: "1"},
+	}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	want := `{"Addr":"` + eip55Vectors[0] + `","Hash":"` + hash.Hex() + `","Balances":{"` + eip55Vectors[0] + `":"1"}}`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+
+	var out struct {
+		Addr     types.Address
+		Hash     types.Hash
+		Balances map[types.Address]string
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if out.Addr != addr || out.Hash != hash || out.Balances[addr] != "1" {
+		t.Errorf("round trip mismatch: %+v", out)
+	}
+
+	if err := json.Unmarshal([]byte(`{"Hash":"0x1234"}`), &out); !errors.Is(err, types.ErrInvalidHash) {
+		t.Errorf("expected ErrInvalidHash, got %v", err)
+	}
+}
+
+func TestBytesToAddress(t *testing.T) {
+	addr := types.BytesToAddress([]byte{0x01, 0x02})
+	if addr.Hex() != "0x0000000000000000000000000000000000000102" {
+		t.Errorf("expected left-padded address, got %s", addr.Hex())
+	}
+
+	if !(types.Address{}).IsZero() || addr.IsZero() {
+		t.Error("IsZero mismatch")
+	}
+}
+
+func TestContractCreationTransfersNoValue(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+
+	block := types.NewBlock(1, types.Hash{}, 30_000_000, uint256.NewInt(1_000_000_000), miner)
+
+	// A nil To marks contract creation; the zero address is an ordinary recipient
+	create := &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		From:                 alice,
+		MaxPriorityFeePerGas: uint256.NewInt(1),
+		MaxFeePerGas:         uint256.NewInt(2_000_000_000),
+		GasLimit:             100_000,
+		Value:                uint256.NewInt(500),
+		Data:                 []byte{0x60, 0x00},
+	}
+
+	if result := executor.ExecuteTransaction(create, block, state); !result.Success {
+		t.Fatalf("contract creation failed: %v", result.Error)
+	}
+	if got := state.GetBalance(types.Address{}); !got.IsZero() {
+		t.Errorf("contract creation must not credit the zero address, got %s", got)
+	}
+
+	zero := types.Address{}
+	transfer := &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		From:                 alice,
+		To:                   &zero,
+		Nonce:                1,
+		MaxPriorityFeePerGas: uint256.NewInt(1),
+		MaxFeePerGas:         uint256.NewInt(2_000_000_000),
+		GasLimit:             21_000,
+		Value:                uint256.NewInt(500),
+	}
+
+	if result := executor.ExecuteTransaction(transfer, block, state); !result.Success {
+		t.Fatalf("transfer failed: %v", result.Error)
+	}
+	if got := state.GetBalance(types.Address{}); got != uint256.NewInt(500) {
+		t.Errorf("expected zero address balance 500, got %s", got)
+	}
+}
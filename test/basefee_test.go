@@ -6,6 +6,7 @@ import (
 	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
 	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
 	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
 )
 
 func TestBaseFeeCalculation(t *testing.T) {
@@ -13,50 +14,50 @@ func TestBaseFeeCalculation(t *testing.T) {
 		name            string
 		gasUsed         uint64
 		gasLimit        uint64
-		currentBaseFee  uint64
-		expectedBaseFee uint64
+		currentBaseFee  uint256.Int
+		expectedBaseFee uint256.Int
 	}{
 		{
 			name:            "at target - no change",
 			gasUsed:         15_000_000,
 			gasLimit:        30_000_000,
-			currentBaseFee:  1_000_000_000,
-			expectedBaseFee: 1_000_000_000,
+			currentBaseFee:  uint256.NewInt(1_000_000_000),
+			expectedBaseFee: uint256.NewInt(1_000_000_000),
 		},
 		{
 			name:            "above target - increase",
 			gasUsed:         20_000_000,
 			gasLimit:        30_000_000,
-			currentBaseFee:  1_000_000_000,
-			expectedBaseFee: 1_041_666_666,
+			currentBaseFee:  uint256.NewInt(1_000_000_000),
+			expectedBaseFee: uint256.NewInt(1_041_666_666),
 		},
 		{
 			name:            "below target - decrease",
 			gasUsed:         10_000_000,
 			gasLimit:        30_000_000,
-			currentBaseFee:  1_000_000_000,
-			expectedBaseFee: 958_333_334,
+			currentBaseFee:  uint256.NewInt(1_000_000_000),
+			expectedBaseFee: uint256.NewInt(958_333_334),
 		},
 		{
 			name:            "full block - max increase",
 			gasUsed:         30_000_000,
 			gasLimit:        30_000_000,
-			currentBaseFee:  1_000_000_000,
-			expectedBaseFee: 1_125_000_000,
+			currentBaseFee:  uint256.NewInt(1_000_000_000),
+			expectedBaseFee: uint256.NewInt(1_125_000_000),
 		},
 		{
 			name:            "empty block - max decrease",
 			gasUsed:         0,
 			gasLimit:        30_000_000,
-			currentBaseFee:  1_000_000_000,
-			expectedBaseFee: 875_000_000,
+			currentBaseFee:  uint256.NewInt(1_000_000_000),
+			expectedBaseFee: uint256.NewInt(875_000_000),
 		},
 		{
 			name:            "very low base fee decrease",
 			gasUsed:         0,
 			gasLimit:        30_000_000,
-			currentBaseFee:  100,
-			expectedBaseFee: 88,
+			currentBaseFee:  uint256.NewInt(100),
+			expectedBaseFee: uint256.NewInt(88),
 		},
 	}
 
@@ -69,10 +70,10 @@ func TestBaseFeeCalculation(t *testing.T) {
 				BaseFee:  tt.currentBaseFee,
 			}
 
-			result := basefee.Calculate(parent)
+			result := basefee.Calculate(constants.MainnetConfig, parent)
 
 			if result != tt.expectedBaseFee {
-				t.Errorf("expected base fee %d, got %d", tt.expectedBaseFee, result)
+				t.Errorf("expected base fee %s, got %s", tt.expectedBaseFee, result)
 			}
 		})
 	}
@@ -83,12 +84,12 @@ func TestBaseFeeNeverNegative(t *testing.T) {
 		Number:   constants.ForkBlockNumber,
 		GasLimit: 30_000_000,
 		GasUsed:  0,
-		BaseFee:  10, // Very low base fee
+		BaseFee:  uint256.NewInt(10), // Very low base fee
 	}
 
-	result := basefee.Calculate(parent)
+	result := basefee.Calculate(constants.MainnetConfig, parent)
 
-	if result > parent.BaseFee {
+	if result.Gt(parent.BaseFee) {
 		t.Errorf("base fee should decrease, but increased from %d to %d", parent.BaseFee, result)
 	}
 }
@@ -98,13 +99,13 @@ func TestBaseFeeMinimumIncrease(t *testing.T) {
 		Number:   constants.ForkBlockNumber,
 		GasLimit: 30_000_000,
 		GasUsed:  15_000_001, // Just 1 wei above target
-		BaseFee:  1,
+		BaseFee:  uint256.NewInt(1),
 	}
 
-	result := basefee.Calculate(parent)
+	result := basefee.Calculate(constants.MainnetConfig, parent)
 
 	// Should increase by at least 1 wei
-	if result <= parent.BaseFee {
+	if result.Cmp(parent.BaseFee) <= 0 {
 		t.Errorf("base fee should increase by at least 1, but got %d", result)
 	}
 }
@@ -0,0 +1,289 @@
+package test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func blobHash(b byte) types.Hash {
+	var h types.Hash
+	h[0] = constants.BlobHashVersionKZG
+	h[31] = b
+	return h
+}
+
+func newBlobTx(nonce uint64, blobs int) *types.Transaction {
+	tx := &types.Transaction{
+		Type:                 types.BlobTxType,
+		ChainID:              1,
+		Nonce:                nonce,
+		MaxPriorityFeePerGas: uint256.NewInt(1_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(5_000_000_000),
+		GasLimit:             21_000,
+		To:                   &bob,
+		MaxFeePerBlobGas:     uint256.NewInt(10),
+	}
+	for i := 0; i < blobs; i++ {
+		tx.BlobHashes = append(tx.BlobHashes, blobHash(byte(i)))
+	}
+	return tx
+}
+
+func TestFakeExponential(t *testing.T) {
+	tests := []struct {
+		factor, numerator, denominator uint64
+		want                           uint64
+	}{
+		{1, 0, 1, 1},
+		{38493, 0, 1000, 38493},
+		{0, 1234, 2345, 0},
+		{1, 2, 1, 6},
+		{1, 4, 2, 6},
+		{1, 3, 1, 16},
+		{1, 6, 2, 18},
+		{1, 4, 1, 49},
+		{1, 8, 2, 50},
+		{10, 8, 2, 542},
+		{11, 8, 2, 596},
+		{1, 5, 1, 136},
+		{1, 5, 2, 11},
+		{2, 5, 2, 23},
+		{1, 50_000_000, 2_225_652, 5_709_098_764},
+	}
+
+	for _, tt := range tests {
+		got := basefee.FakeExponential(uint256.NewInt(tt.factor), uint256.NewInt(tt.numerator), uint256.NewInt(tt.denominator))
+		if got != uint256.NewInt(tt.want) {
+			t.Errorf("fakeExponential(%d, %d, %d): expected %d, got %s", tt.factor, tt.numerator, tt.denominator, tt.want, got)
+		}
+	}
+
+	// Far beyond any reachable excess the result saturates instead of wrapping
+	if got := basefee.FakeExponential(uint256.NewInt(1), uint256.NewInt(^uint64(0)), uint256.NewInt(1)); got != uint256.Max {
+		t.Errorf("expected saturation at max, got %s", got)
+	}
+}
+
+func TestCalculateBlobBaseFee(t *testing.T) {
+	tests := []struct {
+		excessBlobGas uint64
+		want          uint64
+	}{
+		{0, 1},
+		{2_314_057, 1},
+		{2_314_058, 2},
+		{10 * 1024 * 1024, 23},
+	}
+
+	for _, tt := range tests {
+		if got := basefee.CalculateBlobBaseFee(tt.excessBlobGas); got != uint256.NewInt(tt.want) {
+			t.Errorf("excess %d: expected blob base fee %d, got %s", tt.excessBlobGas, tt.want, got)
+		}
+	}
+}
+
+func TestCalculateExcessBlobGas(t *testing.T) {
+	target := constants.TargetBlobGasPerBlock
+	tests := []struct {
+		name               string
+		excess, used, want uint64
+	}{
+		{name: "below target", excess: 0, used: target - 1, want: 0},
+		{name: "at target", excess: 0, used: target, want: 0},
+		{name: "full block", excess: 0, used: constants.MaxBlobGasPerBlock, want: target},
+		{name: "empty block drains excess", excess: target + 5, used: 0, want: 5},
+		{name: "carried forward", excess: 1_000, used: target + constants.BlobGasPerBlob, want: 1_000 + constants.BlobGasPerBlob},
+	}
+
+	for _, tt := range tests {
+		parent := &types.Block{ExcessBlobGas: tt.excess, BlobGasUsed: tt.used}
+		if got, err := basefee.CalculateExcessBlobGas(parent); err != nil || got != tt.want {
+			t.Errorf("%s: expected %d, got %d (%v)", tt.name, tt.want, got, err)
+		}
+	}
+
+	parent := &types.Block{ExcessBlobGas: math.MaxUint64, BlobGasUsed: constants.BlobGasPerBlob}
+	if _, err := basefee.CalculateExcessBlobGas(parent); !errors.Is(err, types.ErrGasUintOverflow) {
+		t.Errorf("expected ErrGasUintOverflow, got %v", err)
+	}
+}
+
+func TestBlobTransactionRoundTrip(t *testing.T) {
+	tx := signTx(t, newBlobTx(0, 2), aliceKey)
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if enc[0] != types.BlobTxType {
+		t.Fatalf("expected type byte 0x03, got 0x%02x", enc[0])
+	}
+
+	dec, err := types.DecodeTransaction(enc)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if dec.MaxFeePerBlobGas != tx.MaxFeePerBlobGas || len(dec.BlobHashes) != 2 || dec.BlobHashes[1] != tx.BlobHashes[1] {
+		t.Errorf("blob fields changed across round trip: %+v", dec)
+	}
+	if dec.Hash() != tx.Hash() {
+		t.Error("hash changed across round trip")
+	}
+	if sender, err := dec.Sender(); err != nil || sender != alice {
+		t.Errorf("expected sender %s, got %s (%v)", alice, sender, err)
+	}
+}
+
+func TestBlobTransactionValidation(t *testing.T) {
+	baseFee := uint256.NewInt(1_000_000_000)
+	tests := []struct {
+		name   string
+		mutate func(tx *types.Transaction)
+		errIs  error
+	}{
+		{name: "valid", mutate: func(tx *types.Transaction) {}},
+		{name: "contract creation", mutate: func(tx *types.Transaction) { tx.To = nil }, errIs: types.ErrBlobTxCreate},
+		{name: "no blobs", mutate: func(tx *types.Transaction) { tx.BlobHashes = nil }, errIs: types.ErrMissingBlobHashes},
+		{name: "bad version", mutate: func(tx *types.Transaction) { tx.BlobHashes[0][0] = 0x00 }, errIs: types.ErrBlobHashVersion},
+		{
+			name:   "too many blobs",
+			mutate: func(tx *types.Transaction) { *tx = *newBlobTx(0, 7) },
+			errIs:  types.ErrBlobGasLimitExceeded,
+		},
+		{
+			name:   "blob hashes on a type-2 transaction",
+			mutate: func(tx *types.Transaction) { tx.Type = types.DynamicFeeTxType },
+			errIs:  types.ErrMalformedTransaction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newBlobTx(0, 1)
+			tt.mutate(tx)
+			err := tx.Validate(baseFee)
+			if tt.errIs == nil && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+				t.Errorf("expected %v, got %v", tt.errIs, err)
+			}
+		})
+	}
+}
+
+func TestValidateTransactionChecksBlobFeeCap(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+
+	// An excess of 10 MiB of blob gas puts the blob base fee at 23 wei
+	header := &types.Block{BaseFee: uint256.NewInt(1_000_000_000), ExcessBlobGas: 10 * 1024 * 1024}
+	tx := signTx(t, newBlobTx(0, 1), aliceKey)
+
+	err := validator.ValidateTransaction(constants.MainnetConfig, tx, header, state)
+	var feeErr *types.BlobFeeCapError
+	if !errors.As(err, &feeErr) || feeErr.BlobBaseFee != uint256.NewInt(23) {
+		t.Fatalf("expected BlobFeeCapError at blob base fee 23, got %v", err)
+	}
+
+	header.ExcessBlobGas = 0
+	if err := validator.ValidateTransaction(constants.MainnetConfig, tx, header, state); err != nil {
+		t.Errorf("expected no error at the minimum blob base fee, got %v", err)
+	}
+}
+
+func TestExecuteBurnsBlobFee(t *testing.T) {
+	state := types.NewState()
+	initial := uint256.NewInt(1_000_000_000_000_000)
+	state.SetAccount(alice, types.NewAccount(alice, initial))
+
+	// Blob base fee 2 wei; the sender offers up to 10
+	block := types.NewBlock(1, types.Hash{}, 30_000_000, uint256.NewInt(1_000_000_000), miner)
+	block.ExcessBlobGas = 2_314_058
+
+	tx := signTx(t, newBlobTx(0, 2), aliceKey)
+	if err := block.AddTransaction(tx); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if block.BlobGasUsed != 2*constants.BlobGasPerBlob {
+		t.Errorf("expected block blob gas %d, got %d", 2*constants.BlobGasPerBlob, block.BlobGasUsed)
+	}
+
+	result := executor.ExecuteTransaction(tx, block, state)
+	if !result.Success {
+		t.Fatalf("execution failed: %v", result.Error)
+	}
+
+	wantBlobFee := uint256.NewInt(2 * constants.BlobGasPerBlob * 2)
+	if result.BlobFeeAmount != wantBlobFee {
+		t.Errorf("expected blob fee %s, got %s", wantBlobFee, result.BlobFeeAmount)
+	}
+
+	spent := result.BaseFeeAmount.Add(result.TipAmount).Add(result.BlobFeeAmount)
+	if got := state.GetBalance(alice); got != initial.Sub(spent) {
+		t.Errorf("expected balance %s, got %s", initial.Sub(spent), got)
+	}
+}
+
+func TestValidateBlockBlobGas(t *testing.T) {
+	parent := &types.Block{
+		Number:        constants.ForkBlockNumber,
+		GasLimit:      30_000_000,
+		GasUsed:       15_000_000,
+		BaseFee:       uint256.NewInt(1_000_000_000),
+		BlobGasUsed:   constants.MaxBlobGasPerBlock,
+		ExcessBlobGas: 0,
+	}
+
+	newBlock := func() *types.Block {
+		block := types.NewBlock(parent.Number+1, parent.Hash(), 30_000_000, parent.BaseFee, miner)
+		block.Timestamp = parent.Timestamp + 12
+		block.ExcessBlobGas = constants.TargetBlobGasPerBlock
+		if err := block.AddTransaction(newBlobTx(0, 1)); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+		block.GasUsed = 15_000_000
+		return block
+	}
+
+	if err := validator.ValidateBlock(constants.MainnetConfig, newBlock(), parent, testNow); err != nil {
+		t.Fatalf("expected valid block, got %v", err)
+	}
+
+	block := newBlock()
+	block.ExcessBlobGas = 0
+	var excessErr *validator.ExcessBlobGasError
+	if err := validator.ValidateBlock(constants.MainnetConfig, block, parent, testNow); !errors.As(err, &excessErr) || excessErr.Expected != constants.TargetBlobGasPerBlock {
+		t.Errorf("expected ExcessBlobGasError, got %v", err)
+	}
+
+	block = newBlock()
+	block.BlobGasUsed = 0
+	if err := validator.ValidateBlock(constants.MainnetConfig, block, parent, testNow); !errors.Is(err, validator.ErrBadBlobGasUsed) {
+		t.Errorf("expected ErrBadBlobGasUsed, got %v", err)
+	}
+
+	block = newBlock()
+	block.BlobGasUsed = constants.MaxBlobGasPerBlock + constants.BlobGasPerBlob
+	if err := validator.ValidateBlock(constants.MainnetConfig, block, parent, testNow); !errors.Is(err, types.ErrBlobGasLimitExceeded) {
+		t.Errorf("expected ErrBlobGasLimitExceeded, got %v", err)
+	}
+
+	// A block cannot take on more than six blobs
+	full := types.NewBlock(1, types.Hash{}, 30_000_000, uint256.Zero, miner)
+	for i := 0; i < 6; i++ {
+		if err := full.AddTransaction(newBlobTx(uint64(i), 1)); err != nil {
+			t.Fatalf("add blob %d: %v", i, err)
+		}
+	}
+	if err := full.AddTransaction(newBlobTx(6, 1)); !errors.Is(err, types.ErrBlobGasLimitExceeded) {
+		t.Errorf("expected ErrBlobGasLimitExceeded, got %v", err)
+	}
+}
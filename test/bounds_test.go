@@ -0,0 +1,90 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// wei returns a base fee bound
+func wei(v uint64) *uint256.Int {
+	fee := uint256.NewInt(v)
+	return &fee
+}
+
+func boundedConfig(min, max *uint256.Int) *constants.ChainConfig {
+	config := *constants.MainnetConfig
+	config.MinBaseFee, config.MaxBaseFee = min, max
+	return &config
+}
+
+func TestBaseFeeFloor(t *testing.T) {
+	config := boundedConfig(wei(500_000_000), nil)
+	parent := ruleParent(0)
+
+	// A quiet period decays to the floor and stays there
+	fees := basefee.CalculateForBlocks(config, parent, make([]uint64, 20))
+	if last := fees[len(fees)-1]; last != uint256.NewInt(500_000_000) {
+		t.Errorf("expected the fee to settle at the floor, got %s", last)
+	}
+
+	// Recovery starts from the floor instead of from near zero
+	parent.BaseFee = uint256.NewInt(500_000_000)
+	parent.GasUsed = 30_000_000
+	if got := basefee.Calculate(config, parent); got != uint256.NewInt(562_500_000) {
+		t.Errorf("expected 562500000 after a full block, got %s", got)
+	}
+}
+
+func TestBaseFeeCeiling(t *testing.T) {
+	config := boundedConfig(nil, wei(1_100_000_000))
+	if got := basefee.Calculate(config, ruleParent(30_000_000)); got != uint256.NewInt(1_100_000_000) {
+		t.Errorf("expected the ceiling, got %s", got)
+	}
+
+	// A parent above a newly lowered ceiling is pulled down even at target
+	if got := basefee.Calculate(boundedConfig(nil, wei(900_000_000)), ruleParent(15_000_000)); got != uint256.NewInt(900_000_000) {
+		t.Errorf("expected the ceiling at target, got %s", got)
+	}
+}
+
+func TestValidateBlockEnforcesBounds(t *testing.T) {
+	config := boundedConfig(wei(950_000_000), nil)
+	parent := ruleParent(0)
+	block := types.NewBlock(parent.Number+1, parent.Hash(), 30_000_000, uint256.NewInt(875_000_000), miner)
+	block.Timestamp = parent.Timestamp + 12
+
+	// The unbounded EIP-1559 fee is below the floor
+	err := validator.ValidateBlock(config, block, parent, testNow)
+	var feeErr *validator.BaseFeeError
+	if !errors.As(err, &feeErr) || feeErr.Expected != uint256.NewInt(950_000_000) {
+		t.Fatalf("expected BaseFeeError with the floor, got %v", err)
+	}
+
+	block.BaseFee = uint256.NewInt(950_000_000)
+	if err := validator.ValidateBlock(config, block, parent, testNow); err != nil {
+		t.Errorf("expected the floor to be valid, got %v", err)
+	}
+}
+
+func TestBoundsApplyToEveryRule(t *testing.T) {
+	config := boundedConfig(wei(990_000_000), wei(1_010_000_000))
+	for name, newRule := range basefee.Rules {
+		if got := newRule().NextBaseFee(config, ruleParent(0)); got != uint256.NewInt(990_000_000) {
+			t.Errorf("%s: expected the floor after an empty block, got %s", name, got)
+		}
+		if got := newRule().NextBaseFee(config, ruleParent(30_000_000)); got != uint256.NewInt(1_010_000_000) {
+			t.Errorf("%s: expected the ceiling after a full block, got %s", name, got)
+		}
+	}
+
+	// The independent spec implementation applies the same bounds
+	if err := basefee.CrossCheck(config, ruleParent(0), []uint64{0, 30_000_000, 30_000_000, 0, 15_000_000}); err != nil {
+		t.Error(err)
+	}
+}
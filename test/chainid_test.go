@@ -0,0 +1,85 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func newTransfer(chainID uint64) *types.Transaction {
+	return &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		ChainID:              chainID,
+		To:                   &bob,
+		MaxPriorityFeePerGas: uint256.NewInt(1_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(5_000_000_000),
+		GasLimit:             21_000,
+		Value:                uint256.NewInt(1_000),
+	}
+}
+
+func TestValidateTransactionChainID(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+	header := &types.Block{BaseFee: uint256.NewInt(1_000_000_000)}
+
+	tx := signTx(t, newTransfer(1), aliceKey)
+	if err := validator.ValidateTransaction(constants.MainnetConfig, tx, header, state); err != nil {
+		t.Fatalf("expected a mainnet transaction to be valid on mainnet, got %v", err)
+	}
+
+	// The same signed transaction replayed on Optimism
+	err := validator.ValidateTransaction(constants.OptimismConfig, tx, header, state)
+	var chainErr *types.ChainIDError
+	if !errors.As(err, &chainErr) || chainErr.Expected != 10 || chainErr.Got != 1 {
+		t.Errorf("expected ChainIDError 10 vs 1, got %v", err)
+	}
+
+	// Unprotected legacy transactions predate EIP-155 and are valid everywhere
+	legacy := signTx(t, &types.Transaction{
+		Type:     types.LegacyTxType,
+		To:       &bob,
+		GasPrice: uint256.NewInt(2_000_000_000),
+		GasLimit: 21_000,
+	}, aliceKey)
+	if err := validator.ValidateTransaction(constants.OptimismConfig, legacy, header, state); err != nil {
+		t.Errorf("expected an unprotected legacy transaction to be valid, got %v", err)
+	}
+}
+
+func TestChainIDInSigningDomain(t *testing.T) {
+	for _, txType := range []byte{types.LegacyTxType, types.DynamicFeeTxType} {
+		tx := newTransfer(1)
+		tx.Type = txType
+		tx.GasPrice = uint256.NewInt(2_000_000_000)
+		signTx(t, tx, aliceKey)
+
+		// Relabelling the chain invalidates the signature instead of moving it
+		tx.ChainID = 10
+		if txType == types.LegacyTxType {
+			tx.V = tx.V.Add(uint256.NewInt(2 * 9)) // Keep V consistent with the new chain ID
+		}
+		sender, err := tx.Sender()
+		if err == nil && sender == alice {
+			t.Errorf("type %d: signature still recovers the sender on another chain", txType)
+		}
+	}
+}
+
+func TestValidateBlockChainID(t *testing.T) {
+	parent := ruleParent(15_000_000)
+	block := types.NewBlock(parent.Number+1, parent.Hash(), 30_000_000, parent.BaseFee, miner)
+	block.Timestamp = parent.Timestamp + 12
+	if err := block.AddTransaction(signTx(t, newTransfer(10), aliceKey)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := validator.ValidateBlock(constants.MainnetConfig, block, parent, testNow)
+	if !errors.Is(err, validator.ErrInvalidTransaction) || !errors.Is(err, types.ErrInvalidChainID) {
+		t.Errorf("expected ErrInvalidChainID, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func TestChainConfigBaseFee(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          *constants.ChainConfig
+		gasUsed         uint64
+		expectedBaseFee uint256.Int
+	}{
+		{name: "mainnet full block", config: constants.MainnetConfig, gasUsed: 30_000_000, expectedBaseFee: uint256.NewInt(1_125_000_000)},
+		{name: "mainnet at target", config: constants.MainnetConfig, gasUsed: 15_000_000, expectedBaseFee: uint256.NewInt(1_000_000_000)},
+		// Target is 30M / 6 = 5M; a full block is 5x target, so +5/250 = +2%
+		{name: "optimism full block", config: constants.OptimismConfig, gasUsed: 30_000_000, expectedBaseFee: uint256.NewInt(1_020_000_000)},
+		{name: "optimism at target", config: constants.OptimismConfig, gasUsed: 5_000_000, expectedBaseFee: uint256.NewInt(1_000_000_000)},
+		{name: "optimism empty block", config: constants.OptimismConfig, gasUsed: 0, expectedBaseFee: uint256.NewInt(996_000_000)},
+		{name: "base half full", config: constants.BaseConfig, gasUsed: 15_000_000, expectedBaseFee: uint256.NewInt(1_008_000_000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := &types.Block{
+				Number:   tt.config.LondonBlock + 100,
+				GasLimit: 30_000_000,
+				GasUsed:  tt.gasUsed,
+				BaseFee:  uint256.NewInt(1_000_000_000),
+			}
+			if got := basefee.Calculate(tt.config, parent); got != tt.expectedBaseFee {
+				t.Errorf("expected %s, got %s", tt.expectedBaseFee, got)
+			}
+		})
+	}
+}
+
+func TestChainConfigGasTarget(t *testing.T) {
+	block := &types.Block{Number: 200_000_000, GasLimit: 30_000_000, GasUsed: 10_000_000}
+
+	if got := block.GasTarget(constants.MainnetConfig); got != 15_000_000 {
+		t.Errorf("mainnet: expected target 15M, got %d", got)
+	}
+	if got := block.GasTarget(constants.OptimismConfig); got != 5_000_000 {
+		t.Errorf("optimism: expected target 5M, got %d", got)
+	}
+	if !block.IsBelowTarget(constants.MainnetConfig) || !block.IsAboveTarget(constants.OptimismConfig) {
+		t.Error("10M gas should be below the mainnet target and above the optimism target")
+	}
+}
+
+func TestChainConfigForkBlock(t *testing.T) {
+	config := *constants.MainnetConfig
+	config.LondonBlock = 100
+	config.InitialBaseFee = uint256.NewInt(7)
+
+	parent := &types.Block{Number: 99, GasLimit: 30_000_000}
+	if got := basefee.Calculate(&config, parent); got != uint256.NewInt(7) {
+		t.Errorf("expected the configured initial base fee 7, got %s", got)
+	}
+}
+
+func TestValidateBlockUsesChainConfig(t *testing.T) {
+	parent := &types.Block{
+		Number:   constants.OptimismConfig.LondonBlock + 1,
+		GasLimit: 30_000_000,
+		GasUsed:  30_000_000,
+		BaseFee:  uint256.NewInt(1_000_000_000),
+	}
+	block := &types.Block{
+		Number:     parent.Number + 1,
+		ParentHash: parent.Hash(),
+		GasLimit:   30_000_000,
+		BaseFee:    uint256.NewInt(1_020_000_000),
+		Miner:      miner,
+		Timestamp:  parent.Timestamp + 12,
+	}
+
+	if err := validator.ValidateBlock(constants.OptimismConfig, block, parent, testNow); err != nil {
+		t.Errorf("expected valid optimism block, got %v", err)
+	}
+
+	// The same block fails under mainnet rules, which expect a 12.5% increase
+	err := validator.ValidateBlock(constants.MainnetConfig, block, parent, testNow)
+	var feeErr *validator.BaseFeeError
+	if !errors.As(err, &feeErr) || feeErr.Expected != uint256.NewInt(1_125_000_000) {
+		t.Errorf("expected BaseFeeError with expected 1125000000, got %v", err)
+	}
+}
+
+func TestChainConfigPresets(t *testing.T) {
+	for name, config := range constants.ChainConfigs {
+		if config.Name != name {
+			t.Errorf("preset %q is registered as %q", config.Name, name)
+		}
+		if err := config.Validate(); err != nil {
+			t.Errorf("preset %q: %v", name, err)
+		}
+	}
+
+	if constants.MainnetConfig.LondonBlock != constants.ForkBlockNumber || constants.MainnetConfig.BaseFeeChangeDenominator != constants.BaseFeeChangeDenominator {
+		t.Error("mainnet preset should match the mainnet constants")
+	}
+}
+
+func TestChainConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*constants.ChainConfig)
+		valid  bool
+	}{
+		{"mainnet", func(*constants.ChainConfig) {}, true},
+		{"zero denominator", func(c *constants.ChainConfig) { c.BaseFeeChangeDenominator = 0 }, false},
+		{"zero elasticity", func(c *constants.ChainConfig) { c.ElasticityMultiplier = 0 }, false},
+		{"floor above ceiling", func(c *constants.ChainConfig) { c.MinBaseFee, c.MaxBaseFee = wei(2), wei(1) }, false},
+		{"floor only", func(c *constants.ChainConfig) { c.MinBaseFee = wei(2) }, true},
+		{"resources", func(c *constants.ChainConfig) { c.Resources = &constants.DefaultResourceConfig }, true},
+		{"zero resource target", func(c *constants.ChainConfig) {
+			c.Resources = resourceConfigWith(func(p *constants.ResourceParams) { p.Target = 0 })
+		}, false},
+		{"zero resource limit", func(c *constants.ChainConfig) {
+			c.Resources = resourceConfigWith(func(p *constants.ResourceParams) { p.Limit = 0 })
+		}, false},
+		{"zero resource initial base fee", func(c *constants.ChainConfig) {
+			c.Resources = resourceConfigWith(func(p *constants.ResourceParams) { p.InitialBaseFee = uint256.Zero })
+		}, false},
+		{"resource target above limit", func(c *constants.ChainConfig) {
+			c.Resources = resourceConfigWith(func(p *constants.ResourceParams) { p.Target = p.Limit + 1 })
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := *constants.MainnetConfig
+			tt.modify(&config)
+			err := config.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("expected valid, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, constants.ErrInvalidChainConfig) {
+				t.Errorf("expected ErrInvalidChainConfig, got %v", err)
+			}
+		})
+	}
+}
+
+// resourceConfigWith returns the default resource config with the calldata parameters modified
+func resourceConfigWith(modify func(*constants.ResourceParams)) *constants.ResourceConfig {
+	resources := constants.DefaultResourceConfig
+	modify(&resources[constants.CalldataResource])
+	return &resources
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/conformance"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/hexutil"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func loadFixture(t *testing.T, path string) *conformance.Fixture {
+	t.Helper()
+	fixture, err := conformance.LoadFixture(path)
+	if err != nil {
+		t.Fatalf("load %s: %v", path, err)
+	}
+	return fixture
+}
+
+type coverage struct {
+	fork                     bool
+	increaseRun, decreaseRun int // Longest runs of consecutive maximum changes
+	gasLimitChanges          int
+}
+
+// fixtureCoverage reports which transitions of the base fee rule blocks exercise
+func fixtureCoverage(config *constants.ChainConfig, blocks []*types.Block) coverage {
+	var c coverage
+	increases, decreases := 0, 0
+	for i := 1; i < len(blocks); i++ {
+		parent, block := blocks[i-1], blocks[i]
+		if block.Number == config.LondonBlock {
+			c.fork = true
+		}
+		if !config.IsLondon(parent.Number) {
+			continue
+		}
+		if block.GasLimit != parent.GasLimit {
+			c.gasLimitChanges++
+		}
+
+		step := parent.BaseFee.Div(uint256.NewInt(config.BaseFeeChangeDenominator))
+		switch {
+		case block.BaseFee == parent.BaseFee.Add(step):
+			increases, decreases = increases+1, 0
+		case block.BaseFee == parent.BaseFee.Sub(step):
+			increases, decreases = 0, decreases+1
+		default:
+			increases, decreases = 0, 0
+		}
+		c.increaseRun, c.decreaseRun = max(c.increaseRun, increases), max(c.decreaseRun, decreases)
+	}
+	return c
+}
+
+func TestSyntheticConformance(t *testing.T) {
+	fixture := loadFixture(t, "testdata/synthetic_headers.json")
+	if err := fixture.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The fixture covers the London transition with a doubled gas limit
+	blocks := fixture.Blocks()
+	if blocks[1].Number != constants.ForkBlockNumber-1 || !blocks[1].BaseFee.IsZero() ||
+		blocks[2].BaseFee != uint256.NewInt(constants.InitialBaseFee) || blocks[2].GasLimit != 2*blocks[1].GasLimit {
+		t.Errorf("unexpected transition headers: %+v %+v", blocks[1], blocks[2])
+	}
+	if c := fixtureCoverage(constants.MainnetConfig, blocks); !c.fork || c.increaseRun < 2 || c.decreaseRun < 2 || c.gasLimitChanges == 0 {
+		t.Errorf("synthetic fixture does not cover every transition: %+v", c)
+	}
+}
+
+func TestConformanceReportsFirstMismatch(t *testing.T) {
+	fixture := loadFixture(t, "testdata/synthetic_headers.json")
+	fee := uint256.Int(*fixture.Headers[5].BaseFee)
+	tampered := hexutil.U256(fee.Add(uint256.NewInt(1)))
+	fixture.Headers[5].BaseFee = &tampered
+	fixture.Headers[8].GasLimit++
+
+	err := fixture.Verify()
+	var mismatch *conformance.MismatchError
+	if !errors.As(err, &mismatch) || mismatch.Number != uint64(fixture.Headers[5].Number) || mismatch.Expected != fee {
+		t.Fatalf("expected a mismatch at block %d, got %v", fixture.Headers[5].Number, err)
+	}
+
+	// A gas limit beyond the 1/1024 bound is reported by the validator
+	fixture = loadFixture(t, "testdata/synthetic_headers.json")
+	fixture.Headers[9].GasLimit += 1_000_000
+	err = fixture.Verify()
+	var blockErr *conformance.BlockError
+	if !errors.As(err, &blockErr) || blockErr.Number != uint64(fixture.Headers[9].Number) || !errors.Is(err, validator.ErrGasLimitOutOfBounds) {
+		t.Errorf("expected ErrGasLimitOutOfBounds at block %d, got %v", fixture.Headers[9].Number, err)
+	}
+}
+
+func TestConformanceFixtureErrors(t *testing.T) {
+	fixture := loadFixture(t, "testdata/synthetic_headers.json")
+	fixture.Chain = "ropsten"
+	if err := fixture.Verify(); !errors.Is(err, conformance.ErrUnknownChain) {
+		t.Errorf("expected ErrUnknownChain, got %v", err)
+	}
+
+	if err := conformance.Verify(constants.MainnetConfig, fixture.Blocks()[:1]); !errors.Is(err, conformance.ErrTooFewHeaders) {
+		t.Errorf("expected ErrTooFewHeaders, got %v", err)
+	}
+}
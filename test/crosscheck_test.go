@@ -0,0 +1,84 @@
+package test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// The validator used to carry its own copy of the base fee rule, which rejected the fork block
+func TestValidateBlockAcceptsForkBlock(t *testing.T) {
+	config := *constants.MainnetConfig
+	config.LondonBlock = 100
+
+	parent := &types.Block{Number: 99, GasLimit: 15_000_000, GasUsed: 15_000_000}
+	block := &types.Block{
+		Number:     100,
+		ParentHash: parent.Hash(),
+		GasLimit:   30_000_000,
+		BaseFee:    config.InitialBaseFee,
+		Miner:      miner,
+		Timestamp:  parent.Timestamp + 12,
+	}
+
+	if err := validator.ValidateBlock(&config, block, parent, testNow); err != nil {
+		t.Errorf("expected the fork block with the initial base fee to be valid, got %v", err)
+	}
+}
+
+func TestCrossCheckBuiltins(t *testing.T) {
+	if !slices.Contains(basefee.Implementations(), "eip1559-spec") {
+		t.Fatalf("expected the spec implementation to be registered, got %v", basefee.Implementations())
+	}
+
+	sequence := []uint64{30_000_000, 0, 15_000_000, 29_999_999, 1, 22_500_000, 0, 0, 30_000_000}
+	for _, config := range constants.ChainConfigs {
+		initial := &types.Block{Number: config.LondonBlock + 1, GasLimit: 30_000_000, BaseFee: uint256.NewInt(7)}
+		if err := basefee.CrossCheck(config, initial, sequence); err != nil {
+			t.Errorf("%s: %v", config.Name, err)
+		}
+
+		// Fee at the top of the range, where the uint256 product overflows
+		initial.BaseFee = uint256.Max
+		if err := basefee.CrossCheck(config, initial, sequence); err != nil {
+			t.Errorf("%s near max: %v", config.Name, err)
+		}
+	}
+
+	config := *constants.MainnetConfig
+	config.LondonBlock = 100
+	if err := basefee.CrossCheck(&config, &types.Block{Number: 97, GasLimit: 30_000_000}, sequence); err != nil {
+		t.Errorf("across the fork: %v", err)
+	}
+}
+
+func TestCrossCheckReportsMismatch(t *testing.T) {
+	// Forgets the minimum increase of 1 wei
+	basefee.Register("test-no-min-delta", func(config *constants.ChainConfig, parent *types.Block) uint256.Int {
+		got := basefee.Calculate(config, parent)
+		if parent.GasUsed > parent.GasTarget(config) && parent.BaseFee.Lt(uint256.NewInt(8)) {
+			return parent.BaseFee
+		}
+		return got
+	})
+	t.Cleanup(func() { basefee.Unregister("test-no-min-delta") })
+
+	initial := &types.Block{Number: 20_000_000, GasLimit: 30_000_000, GasUsed: 0, BaseFee: uint256.NewInt(9)}
+	err := basefee.CrossCheck(constants.MainnetConfig, initial, []uint64{0, 30_000_000, 0})
+
+	var mismatch *basefee.MismatchError
+	if !errors.As(err, &mismatch) || !errors.Is(err, basefee.ErrMismatch) {
+		t.Fatalf("expected MismatchError, got %v", err)
+	}
+	// 9 -> 8 (empty) -> 7 (empty) -> full block: the reference gives 8, the broken rule 7
+	if mismatch.Implementation != "test-no-min-delta" || mismatch.Number != 20_000_003 ||
+		mismatch.Expected != uint256.NewInt(8) || mismatch.Got != uint256.NewInt(7) {
+		t.Errorf("unexpected mismatch %+v", mismatch)
+	}
+}
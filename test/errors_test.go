@@ -0,0 +1,151 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func TestTransactionErrorsCarryValues(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000)))
+	account := state.GetAccount(alice)
+	account.Nonce = 3
+
+	baseFee := uint256.NewInt(1_000_000_000)
+	header := &types.Block{BaseFee: baseFee}
+	tx := &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		ChainID:              1,
+		From:                 alice,
+		To:                   &bob,
+		Nonce:                3,
+		MaxPriorityFeePerGas: uint256.NewInt(1),
+		MaxFeePerGas:         uint256.NewInt(2_000_000_000),
+		GasLimit:             21_000,
+	}
+
+	err := validator.ValidateTransaction(constants.MainnetConfig, signTx(t, tx, aliceKey), header, state)
+	var fundsErr *types.InsufficientFundsError
+	if !errors.As(err, &fundsErr) {
+		t.Fatalf("expected InsufficientFundsError, got %v", err)
+	}
+	if fundsErr.Balance != uint256.NewInt(1_000) || fundsErr.Cost != uint256.NewInt(42_000_000_000_000) {
+		t.Errorf("unexpected values: have %s, need %s", fundsErr.Balance, fundsErr.Cost)
+	}
+
+	account.Balance = uint256.NewInt(1_000_000_000_000_000)
+	tx.Nonce = 2
+	err = validator.ValidateTransaction(constants.MainnetConfig, signTx(t, tx, aliceKey), header, state)
+	if !errors.Is(err, types.ErrNonceTooLow) {
+		t.Fatalf("expected ErrNonceTooLow, got %v", err)
+	}
+	var nonceErr *types.NonceError
+	if !errors.As(err, &nonceErr) || nonceErr.TxNonce != 2 || nonceErr.StateNonce != 3 {
+		t.Errorf("expected NonceError{2, 3}, got %v", err)
+	}
+
+	tx.Nonce = 3
+	tx.MaxFeePerGas = uint256.NewInt(999_999_999)
+	err = validator.ValidateTransaction(constants.MainnetConfig, signTx(t, tx, aliceKey), header, state)
+	if !errors.Is(err, validator.ErrInvalidTransaction) {
+		t.Errorf("expected ErrInvalidTransaction, got %v", err)
+	}
+	var feeErr *types.FeeCapError
+	if !errors.As(err, &feeErr) || feeErr.BaseFee != baseFee || feeErr.MaxFeePerGas != tx.MaxFeePerGas {
+		t.Errorf("expected FeeCapError with offending values, got %v", err)
+	}
+}
+
+func TestBlockErrorsCarryValues(t *testing.T) {
+	parent := &types.Block{
+		Number:   constants.ForkBlockNumber,
+		GasLimit: 30_000_000,
+		GasUsed:  15_000_000,
+		BaseFee:  uint256.NewInt(1_000_000_000),
+	}
+
+	block := &types.Block{
+		Number:     parent.Number + 1,
+		ParentHash: types.BytesToHash([]byte("other")),
+		GasLimit:   30_000_000,
+		BaseFee:    uint256.NewInt(1_000_000_000),
+		Miner:      miner,
+		Timestamp:  parent.Timestamp + 12,
+	}
+
+	err := validator.ValidateBlock(constants.MainnetConfig, block, parent, testNow)
+	var hashErr *validator.ParentHashError
+	if !errors.As(err, &hashErr) || hashErr.Expected != parent.Hash() || hashErr.Got != types.BytesToHash([]byte("other")) {
+		t.Errorf("expected ParentHashError, got %v", err)
+	}
+
+	block.ParentHash = parent.Hash()
+	block.GasLimit = 29_000_000
+	err = validator.ValidateBlock(constants.MainnetConfig, block, parent, testNow)
+	var limitErr *validator.GasLimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("expected GasLimitError, got %v", err)
+	}
+	if limitErr.Min != 30_000_000-30_000_000/1024 || limitErr.Limit != 29_000_000 {
+		t.Errorf("unexpected bounds: %+v", limitErr)
+	}
+
+	block.GasLimit = 30_000_000
+	block.BaseFee = uint256.NewInt(7)
+	err = validator.ValidateBlock(constants.MainnetConfig, block, parent, testNow)
+	var feeErr *validator.BaseFeeError
+	if !errors.As(err, &feeErr) || feeErr.Expected != parent.BaseFee || feeErr.Got != uint256.NewInt(7) {
+		t.Errorf("expected BaseFeeError, got %v", err)
+	}
+}
+
+func TestExecuteBlockErrorIdentifiesTransaction(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+
+	block := types.NewBlock(1, types.BytesToHash([]byte("parent")), 30_000_000, uint256.NewInt(1_000_000_000), miner)
+	for _, from := range []types.Address{alice, bob} {
+		tx := &types.Transaction{
+			Type:                 types.DynamicFeeTxType,
+			ChainID:              1,
+			From:                 from,
+			To:                   &carol,
+			MaxPriorityFeePerGas: uint256.NewInt(1_000_000_000),
+			MaxFeePerGas:         uint256.NewInt(2_000_000_000),
+			GasLimit:             21_000,
+		}
+		if err := block.AddTransaction(tx); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+
+	_, err := executor.ExecuteBlock(block, state)
+	if !errors.Is(err, executor.ErrTxExecutionFailed) || !errors.Is(err, types.ErrInsufficientFunds) {
+		t.Fatalf("expected insufficient funds execution error, got %v", err)
+	}
+	var execErr *executor.TxExecutionError
+	if !errors.As(err, &execErr) || execErr.Index != 1 {
+		t.Errorf("expected failure at index 1, got %v", err)
+	}
+}
+
+func TestAddTransactionGasLimitExceeded(t *testing.T) {
+	block := types.NewBlock(1, types.BytesToHash([]byte("parent")), 30_000, uint256.Zero, miner)
+	tx := &types.Transaction{GasLimit: 21_000}
+
+	if err := block.AddTransaction(tx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := block.AddTransaction(tx)
+	var gasErr *types.GasLimitExceededError
+	if !errors.As(err, &gasErr) || gasErr.GasUsed != 42_000 || gasErr.GasLimit != 30_000 {
+		t.Errorf("expected GasLimitExceededError{42000, 30000}, got %v", err)
+	}
+}
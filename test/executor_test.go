@@ -5,35 +5,37 @@ import (
 
 	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
 	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
 )
 
 func TestExecuteTransaction(t *testing.T) {
 	// Setup state
 	state := types.NewState()
-	state.SetAccount("0xAlice", types.NewAccount("0xAlice", 1_000_000_000_000_000))
-	state.SetAccount("0xBob", types.NewAccount("0xBob", 0))
-	state.SetAccount("0xMiner", types.NewAccount("0xMiner", 0))
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+	state.SetAccount(bob, types.NewAccount(bob, uint256.Zero))
+	state.SetAccount(miner, types.NewAccount(miner, uint256.Zero))
 
 	// Create block
 	block := &types.Block{
 		Number:   1,
 		GasLimit: 30_000_000,
-		BaseFee:  1_000_000_000,
-		Miner:    "0xMiner",
+		BaseFee:  uint256.NewInt(1_000_000_000),
+		Miner:    miner,
 	}
 
 	// Create transaction
 	tx := &types.Transaction{
-		From:                 "0xAlice",
-		To:                   "0xBob",
+		Type:                 types.DynamicFeeTxType,
+		From:                 alice,
+		To:                   &bob,
 		Nonce:                0,
-		MaxPriorityFeePerGas: 2_000_000_000,
-		MaxFeePerGas:         5_000_000_000,
+		MaxPriorityFeePerGas: uint256.NewInt(2_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(5_000_000_000),
 		GasLimit:             21_000,
-		Value:                1_000,
+		Value:                uint256.NewInt(1_000),
 	}
 
-	initialAliceBalance := state.GetBalance("0xAlice")
+	initialAliceBalance := state.GetBalance(alice)
 
 	// Execute transaction
 	result := executor.ExecuteTransaction(tx, block, state)
@@ -48,58 +50,63 @@ func TestExecuteTransaction(t *testing.T) {
 	}
 
 	// Check base fee was burned (not given to anyone)
-	expectedBurned := result.GasUsed * block.BaseFee
+	expectedBurned := uint256.NewInt(result.GasUsed).Mul(block.BaseFee)
 	if result.BaseFeeAmount != expectedBurned {
-		t.Errorf("expected burned %d, got %d", expectedBurned, result.BaseFeeAmount)
+		t.Errorf("expected burned %s, got %s", expectedBurned, result.BaseFeeAmount)
 	}
 
 	// Check miner received tip
-	minerBalance := state.GetBalance("0xMiner")
+	minerBalance := state.GetBalance(miner)
 	if minerBalance != result.TipAmount {
-		t.Errorf("expected miner balance %d, got %d", result.TipAmount, minerBalance)
+		t.Errorf("expected miner balance %s, got %s", result.TipAmount, minerBalance)
 	}
 
 	// Check Bob received value
-	bobBalance := state.GetBalance("0xBob")
+	bobBalance := state.GetBalance(bob)
 	if bobBalance != tx.Value {
-		t.Errorf("expected Bob balance %d, got %d", tx.Value, bobBalance)
+		t.Errorf("expected Bob balance %s, got %s", tx.Value, bobBalance)
 	}
 
 	// Check Alice paid correctly
-	aliceBalance := state.GetBalance("0xAlice")
-	expectedAlicePaid := result.GasUsed*tx.EffectiveGasPrice(block.BaseFee) + tx.Value
-	expectedAliceBalance := initialAliceBalance - expectedAlicePaid
+	aliceBalance := state.GetBalance(alice)
+	effectiveGasPrice, err := tx.EffectiveGasPrice(block.BaseFee)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedAlicePaid := uint256.NewInt(result.GasUsed).Mul(effectiveGasPrice).Add(tx.Value)
+	expectedAliceBalance := initialAliceBalance.Sub(expectedAlicePaid)
 
 	if aliceBalance != expectedAliceBalance {
-		t.Errorf("expected Alice balance %d, got %d", expectedAliceBalance, aliceBalance)
+		t.Errorf("expected Alice balance %s, got %s", expectedAliceBalance, aliceBalance)
 	}
 
 	// Check nonce incremented
-	if state.GetNonce("0xAlice") != 1 {
-		t.Errorf("expected Alice nonce 1, got %d", state.GetNonce("0xAlice"))
+	if state.GetNonce(alice) != 1 {
+		t.Errorf("expected Alice nonce 1, got %d", state.GetNonce(alice))
 	}
 }
 
 func TestExecuteTransactionInsufficientFunds(t *testing.T) {
 	state := types.NewState()
-	state.SetAccount("0xAlice", types.NewAccount("0xAlice", 1_000)) // Very low balance
-	state.SetAccount("0xMiner", types.NewAccount("0xMiner", 0))
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000))) // Very low balance
+	state.SetAccount(miner, types.NewAccount(miner, uint256.Zero))
 
 	block := &types.Block{
 		Number:   1,
 		GasLimit: 30_000_000,
-		BaseFee:  1_000_000_000,
-		Miner:    "0xMiner",
+		BaseFee:  uint256.NewInt(1_000_000_000),
+		Miner:    miner,
 	}
 
 	tx := &types.Transaction{
-		From:                 "0xAlice",
-		To:                   "0xBob",
+		Type:                 types.DynamicFeeTxType,
+		From:                 alice,
+		To:                   &bob,
 		Nonce:                0,
-		MaxPriorityFeePerGas: 2_000_000_000,
-		MaxFeePerGas:         5_000_000_000,
+		MaxPriorityFeePerGas: uint256.NewInt(2_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(5_000_000_000),
 		GasLimit:             21_000,
-		Value:                1_000,
+		Value:                uint256.NewInt(1_000),
 	}
 
 	result := executor.ExecuteTransaction(tx, block, state)
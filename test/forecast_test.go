@@ -0,0 +1,116 @@
+package test
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// history returns consecutive post-London blocks with the given gas used, oldest first
+func history(gasUsed ...uint64) []*types.Block {
+	blocks := make([]*types.Block, len(gasUsed))
+	for i, used := range gasUsed {
+		blocks[i] = &types.Block{
+			Number:   constants.ForkBlockNumber + 100 + uint64(i),
+			GasLimit: 30_000_000,
+			GasUsed:  used,
+			BaseFee:  uint256.NewInt(20_000_000_000),
+		}
+	}
+	return blocks
+}
+
+func TestFitGasModel(t *testing.T) {
+	if _, err := basefee.FitGasModel(history(15_000_000)); !errors.Is(err, basefee.ErrInsufficientHistory) {
+		t.Errorf("expected ErrInsufficientHistory, got %v", err)
+	}
+
+	model, err := basefee.FitGasModel(history(0, 30_000_000, 0, 30_000_000, 0, 30_000_000))
+	if err != nil {
+		t.Fatalf("fit: %v", err)
+	}
+	if model.Mean != 0.5 || model.StdDev != 0.5 || model.Autocorrelation >= 0 {
+		t.Errorf("expected mean 0.5, stddev 0.5 and negative autocorrelation, got %+v", model)
+	}
+}
+
+func TestForecastDeterministicLoad(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	// Constant full blocks leave no uncertainty: every band collapses onto the replayed fee
+	recent := history(30_000_000, 30_000_000, 30_000_000)
+	bands, err := basefee.Forecast(constants.MainnetConfig, recent, 5, 100, rng)
+	if err != nil {
+		t.Fatalf("forecast: %v", err)
+	}
+	want := basefee.CalculateForBlocks(constants.MainnetConfig, recent[2], []uint64{30_000_000, 30_000_000, 30_000_000, 30_000_000, 30_000_000})
+	for i, band := range bands {
+		if band.Number != recent[2].Number+uint64(i)+1 {
+			t.Errorf("band %d: unexpected block number %d", i, band.Number)
+		}
+		if band.P10 != want[i] || band.P50 != want[i] || band.P90 != want[i] {
+			t.Errorf("band %d: expected %s, got %+v", i, want[i], band)
+		}
+	}
+}
+
+func TestForecastBands(t *testing.T) {
+	recent := history(10_000_000, 25_000_000, 30_000_000, 5_000_000, 18_000_000, 22_000_000, 12_000_000, 29_000_000)
+
+	bands, err := basefee.Forecast(constants.MainnetConfig, recent, 25, 500, rand.New(rand.NewPCG(7, 7)))
+	if err != nil {
+		t.Fatalf("forecast: %v", err)
+	}
+	if len(bands) != 25 {
+		t.Fatalf("expected 25 bands, got %d", len(bands))
+	}
+
+	// The next block's fee is fixed by the last known block
+	next := basefee.Calculate(constants.MainnetConfig, recent[len(recent)-1])
+	if bands[0].P10 != next || bands[0].P90 != next {
+		t.Errorf("expected the first band to be exactly %s, got %+v", next, bands[0])
+	}
+
+	for i, band := range bands {
+		if band.P10.Gt(band.P50) || band.P50.Gt(band.P90) {
+			t.Errorf("band %d is not ordered: %+v", i, band)
+		}
+	}
+	first, last := bands[1], bands[len(bands)-1]
+	if !last.P90.Sub(last.P10).Gt(first.P90.Sub(first.P10)) {
+		t.Errorf("expected the band to widen with the horizon: %+v then %+v", first, last)
+	}
+
+	// The same seed reproduces the forecast
+	again, _ := basefee.Forecast(constants.MainnetConfig, recent, 25, 500, rand.New(rand.NewPCG(7, 7)))
+	if again[24] != bands[24] {
+		t.Errorf("expected a reproducible forecast, got %+v and %+v", bands[24], again[24])
+	}
+}
+
+func TestForecastInvalidArgs(t *testing.T) {
+	recent := history(15_000_000, 20_000_000, 10_000_000)
+	tests := []struct {
+		name               string
+		blocksAhead, paths int
+	}{
+		{"no blocks", 0, 100},
+		{"negative blocks", -1, 100},
+		{"no paths", 5, 0},
+		{"negative paths", 5, -3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bands, err := basefee.Forecast(constants.MainnetConfig, recent, tt.blocksAhead, tt.paths, rand.New(rand.NewPCG(1, 1)))
+			if !errors.Is(err, basefee.ErrInvalidForecastArgs) || bands != nil {
+				t.Errorf("expected ErrInvalidForecastArgs and no bands, got %v, %v", bands, err)
+			}
+		})
+	}
+}
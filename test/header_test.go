@@ -0,0 +1,105 @@
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/crypto"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func TestKeccak256(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "", expected: "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{input: "abc", expected: "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{input: "The quick brown fox jumps over the lazy dog", expected: "4d741b6f1eb29cb2a9b9911c82f56fa8d73b04959d3d9d222895df6c0b28aa15"},
+	}
+
+	for _, tt := range tests {
+		if got := hex.EncodeToString(crypto.Keccak256([]byte(tt.input))); got != tt.expected {
+			t.Errorf("keccak256(%q): expected %s, got %s", tt.input, tt.expected, got)
+		}
+	}
+
+	// Streaming writes that straddle the 136-byte rate must match a one-shot hash
+	data := bytes.Repeat([]byte("eip-1559"), 100)
+	h := crypto.NewKeccak256()
+	for i := 0; i < len(data); i += 7 {
+		h.Write(data[i:min(i+7, len(data))])
+	}
+	if !bytes.Equal(h.Sum(nil), crypto.Keccak256(data)) {
+		t.Error("streaming keccak256 does not match one-shot digest")
+	}
+}
+
+func TestBlockHashDerivedFromHeader(t *testing.T) {
+	block := &types.Block{
+		Number:   constants.ForkBlockNumber,
+		GasLimit: 30_000_000,
+		GasUsed:  15_000_000,
+		BaseFee:  uint256.NewInt(1_000_000_000),
+		Miner:    miner,
+	}
+
+	hash := block.Hash()
+	if hash.IsZero() {
+		t.Fatal("block hash should not be zero")
+	}
+	if block.Hash() != hash {
+		t.Error("block hash should be deterministic")
+	}
+
+	// Every header field, including baseFeePerGas, must affect the hash
+	mutations := map[string]func(b *types.Block){
+		"number":        func(b *types.Block) { b.Number++ },
+		"parentHash":    func(b *types.Block) { b.ParentHash[0] ^= 1 },
+		"miner":         func(b *types.Block) { b.Miner[19] ^= 1 },
+		"gasLimit":      func(b *types.Block) { b.GasLimit++ },
+		"gasUsed":       func(b *types.Block) { b.GasUsed++ },
+		"timestamp":     func(b *types.Block) { b.Timestamp++ },
+		"extraData":     func(b *types.Block) { b.Extra = []byte{1} },
+		"txCount":       func(b *types.Block) { b.TxCount++ },
+		"baseFee":       func(b *types.Block) { b.BaseFee = b.BaseFee.Add(uint256.NewInt(1)) },
+		"blobGasUsed":   func(b *types.Block) { b.BlobGasUsed++ },
+		"excessBlobGas": func(b *types.Block) { b.ExcessBlobGas++ },
+	}
+
+	for field, mutate := range mutations {
+		tampered := *block
+		mutate(&tampered)
+		if tampered.Hash() == hash {
+			t.Errorf("changing %s did not change the block hash", field)
+		}
+	}
+}
+
+func TestValidateBlockDetectsTamperedParent(t *testing.T) {
+	parent := &types.Block{
+		Number:   constants.ForkBlockNumber,
+		GasLimit: 30_000_000,
+		GasUsed:  15_000_000,
+		BaseFee:  uint256.NewInt(1_000_000_000),
+		Miner:    miner,
+	}
+
+	block := types.NewBlock(parent.Number+1, parent.Hash(), 30_000_000, uint256.NewInt(1_000_000_000), miner)
+	block.Timestamp = parent.Timestamp + 12
+	if err := validator.ValidateBlock(constants.MainnetConfig, block, parent, testNow); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Tampering with any parent header field breaks the parent hash link
+	parent.Miner = alice
+	err := validator.ValidateBlock(constants.MainnetConfig, block, parent, testNow)
+	if !errors.Is(err, validator.ErrBadParentHash) {
+		t.Errorf("expected ErrBadParentHash, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/crypto"
+)
+
+// testNow is the local clock blocks are validated against, after every test block's timestamp
+const testNow uint64 = 2_000_000_000
+
+// Well-known test keys and the accounts they control
+var (
+	aliceKey = mustKey("0x00000000000000000000000000000000000000000000000000000000000a11ce")
+	bobKey   = mustKey("0x0000000000000000000000000000000000000000000000000000000000000b0b")
+	whaleKey = mustKey("0x0000000000000000000000000000000000000000000000000000000000083a1e")
+
+	alice = types.PubkeyToAddress(aliceKey.PublicKey)
+	bob   = types.PubkeyToAddress(bobKey.PublicKey)
+	whale = types.PubkeyToAddress(whaleKey.PublicKey)
+	carol = types.HexToAddress("0x00000000000000000000000000000000000ca201")
+	miner = types.HexToAddress("0x0000000000000000000000000000000000c0ffee")
+
+	testKeys = map[types.Address]*crypto.PrivateKey{
+		alice: aliceKey,
+		bob:   bobKey,
+		whale: whaleKey,
+	}
+)
+
+func mustKey(hex string) *crypto.PrivateKey {
+	key, err := crypto.HexToPrivateKey(hex)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// signTx signs tx with key, failing the test on error
+func signTx(t *testing.T, tx *types.Transaction, key *crypto.PrivateKey) *types.Transaction {
+	t.Helper()
+	if err := tx.Sign(key); err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	return tx
+}
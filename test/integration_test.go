@@ -8,56 +8,60 @@ import (
 	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
 	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
 	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
 )
 
 func TestFullBlockProcessing(t *testing.T) {
 	// Initialize state
 	state := types.NewState()
 	// Give Alice enough balance to cover upfront max-fee * gas + value for multiple transactions
-	state.SetAccount("0xAlice", types.NewAccount("0xAlice", 1_000_000_000_000_000))
-	state.SetAccount("0xBob", types.NewAccount("0xBob", 0))
-	state.SetAccount("0xMiner", types.NewAccount("0xMiner", 0))
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+	state.SetAccount(bob, types.NewAccount(bob, uint256.Zero))
+	state.SetAccount(miner, types.NewAccount(miner, uint256.Zero))
 
-	// Create genesis block
+	// Create genesis block (pre-London: no base fee, and half the post-fork gas limit)
 	genesisBlock := &types.Block{
-		Number:   constants.ForkBlockNumber - 1,
-		Hash:     "0xgenesis",
-		GasLimit: 30_000_000,
-		GasUsed:  15_000_000,
-		BaseFee:  constants.InitialBaseFee,
-		Miner:    "0xMiner",
+		Number:    constants.ForkBlockNumber - 1,
+		GasLimit:  15_000_000,
+		GasUsed:   15_000_000,
+		Miner:     miner,
+		Timestamp: 1_700_000_000,
 	}
 
 	// Process 5 blocks
 	currentBlock := genesisBlock
-	totalBurned := uint64(0)
+	totalBurned := uint256.Zero
 
 	for i := 0; i < 5; i++ {
 		// Calculate next base fee
-		nextBaseFee := basefee.Calculate(currentBlock)
+		nextBaseFee := basefee.Calculate(constants.MainnetConfig, currentBlock)
 
 		// Create next block
 		nextBlock := types.NewBlock(
 			currentBlock.Number+1,
-			currentBlock.Hash,
+			currentBlock.Hash(),
 			30_000_000,
 			nextBaseFee,
-			"0xMiner",
+			miner,
 		)
+		nextBlock.Timestamp = currentBlock.Timestamp + constants.SecondsPerSlot
 
 		// Create transaction
 		tx := &types.Transaction{
-			From:                 "0xAlice",
-			To:                   "0xBob",
-			Nonce:                state.GetNonce("0xAlice"),
-			MaxPriorityFeePerGas: 2_000_000_000,
-			MaxFeePerGas:         nextBaseFee + 10_000_000_000,
+			Type:                 types.DynamicFeeTxType,
+			ChainID:              1,
+			From:                 alice,
+			To:                   &bob,
+			Nonce:                state.GetNonce(alice),
+			MaxPriorityFeePerGas: uint256.NewInt(2_000_000_000),
+			MaxFeePerGas:         nextBaseFee.Add(uint256.NewInt(10_000_000_000)),
 			GasLimit:             21_000,
-			Value:                1_000,
+			Value:                uint256.NewInt(1_000),
 		}
 
 		// Validate transaction
-		if err := validator.ValidateTransaction(tx, nextBaseFee, state); err != nil {
+		signTx(t, tx, aliceKey)
+		if err := validator.ValidateTransaction(constants.MainnetConfig, tx, nextBlock, state); err != nil {
 			t.Fatalf("block %d: transaction validation failed: %v", i, err)
 		}
 
@@ -66,38 +70,42 @@ func TestFullBlockProcessing(t *testing.T) {
 			t.Fatalf("block %d: failed to add transaction: %v", i, err)
 		}
 
-		// Validate block
-		if err := validator.ValidateBlock(nextBlock, currentBlock); err != nil {
-			t.Fatalf("block %d: block validation failed: %v", i, err)
+		// Execute the block, then seal the gas its receipts used
+		results, err := executor.ExecuteBlock(nextBlock, state)
+		if err != nil {
+			t.Fatalf("block %d: execution failed: %v", i, err)
+		}
+		if err := executor.SealBlock(nextBlock, results); err != nil {
+			t.Fatalf("block %d: %v", i, err)
 		}
 
-		// Execute transaction
-		result := executor.ExecuteTransaction(tx, nextBlock, state)
-		if !result.Success {
-			t.Fatalf("block %d: transaction execution failed: %v", i, result.Error)
+		// Validate block against its parent and receipts
+		if err := validator.ValidateExecutedBlock(constants.MainnetConfig, basefee.DefaultRule, nextBlock, currentBlock, testNow, results); err != nil {
+			t.Fatalf("block %d: block validation failed: %v", i, err)
 		}
+		state.Commit()
 
-		totalBurned += result.BaseFeeAmount
+		totalBurned = totalBurned.Add(results[0].BaseFeeAmount)
 
 		// Move to next block
 		currentBlock = nextBlock
 	}
 
 	// Verify final state
-	if state.GetNonce("0xAlice") != 5 {
-		t.Errorf("expected Alice nonce 5, got %d", state.GetNonce("0xAlice"))
+	if state.GetNonce(alice) != 5 {
+		t.Errorf("expected Alice nonce 5, got %d", state.GetNonce(alice))
 	}
 
-	if state.GetBalance("0xBob") != 5000 { // 5 transactions * 1000 wei
-		t.Errorf("expected Bob balance 5000, got %d", state.GetBalance("0xBob"))
+	if state.GetBalance(bob) != uint256.NewInt(5000) { // 5 transactions * 1000 wei
+		t.Errorf("expected Bob balance 5000, got %s", state.GetBalance(bob))
 	}
 
-	if totalBurned == 0 {
+	if totalBurned.IsZero() {
 		t.Error("expected some ETH to be burned")
 	}
 
-	minerBalance := state.GetBalance("0xMiner")
-	if minerBalance == 0 {
+	minerBalance := state.GetBalance(miner)
+	if minerBalance.IsZero() {
 		t.Error("expected miner to receive tips")
 	}
 
@@ -111,16 +119,16 @@ func TestBaseFeeAdjustmentOverTime(t *testing.T) {
 		Number:   constants.ForkBlockNumber,
 		GasLimit: 30_000_000,
 		GasUsed:  15_000_000,
-		BaseFee:  1_000_000_000,
+		BaseFee:  uint256.NewInt(1_000_000_000),
 	}
 
 	// Simulate congestion (high usage)
 	highUsage := []uint64{25_000_000, 28_000_000, 29_000_000}
-	baseFees := basefee.CalculateForBlocks(parent, highUsage)
+	baseFees := basefee.CalculateForBlocks(constants.MainnetConfig, parent, highUsage)
 
 	// Base fee should increase with high usage
 	for i := 1; i < len(baseFees); i++ {
-		if baseFees[i] <= baseFees[i-1] {
+		if baseFees[i].Cmp(baseFees[i-1]) <= 0 {
 			t.Errorf("base fee should increase with high usage, but didn't at index %d", i)
 		}
 	}
@@ -128,11 +136,11 @@ func TestBaseFeeAdjustmentOverTime(t *testing.T) {
 	// Simulate low usage
 	parent.BaseFee = baseFees[len(baseFees)-1]
 	lowUsage := []uint64{5_000_000, 3_000_000, 1_000_000}
-	baseFees = basefee.CalculateForBlocks(parent, lowUsage)
+	baseFees = basefee.CalculateForBlocks(constants.MainnetConfig, parent, lowUsage)
 
 	// Base fee should decrease with low usage
 	for i := 1; i < len(baseFees); i++ {
-		if baseFees[i] >= baseFees[i-1] {
+		if baseFees[i].Cmp(baseFees[i-1]) >= 0 {
 			t.Errorf("base fee should decrease with low usage, but didn't at index %d", i)
 		}
 	}
@@ -0,0 +1,290 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/hexutil"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// An eth_getBlockByNumber response with full transactions: the EIP-155 example
+// transaction and a type-2 transaction, plus header fields the project ignores
+const rpcBlock = `{
+	"number": "0xc5d488",
+	"hash": "0x0000000000000000000000000000000000000000000000000000000000000001",
+	"parentHash": "0x3de6bb3849a138e6ab0b83a3a00dc7433f1e83f7fd488e4bba78f2fe2631a633",
+	"miner": "0x7777788200b672a42421017f65ede4fc759564c8",
+	"difficulty": "0x1b81c23e7a4ef0",
+	"logsBloom": "0x00",
+	"gasLimit": "0x1c9c364",
+	"gasUsed": "0xa410",
+	"timestamp": "0x610bdaa4",
+	"baseFeePerGas": "0x3b9aca00",
+	"transactions": [
+		{
+			"type": "0x0",
+			"nonce": "0x9",
+			"gasPrice": "0x4a817c800",
+			"gas": "0x5208",
+			"to": "0x3535353535353535353535353535353535353535",
+			"value": "0xde0b6b3a7640000",
+			"input": "0x",
+			"from": "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f",
+			"v": "0x25",
+			"r": "0x28ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276",
+			"s": "0x67cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+		},
+		{
+			"type": "0x2",
+			"chainId": "0x1",
+			"nonce": "0x0",
+			"gasPrice": "0x77359400",
+			"maxPriorityFeePerGas": "0x3b9aca00",
+			"maxFeePerGas": "0xba43b7400",
+			"gas": "0x5208",
+			"to": null,
+			"value": "0x0",
+			"input": "0xcafe",
+			"accessList": [{"address": "0x0000000000000000000000000000000000000b0b", "storageKeys": []}],
+			"v": "0x1",
+			"r": "0x1",
+			"s": "0x2",
+			"yParity": "0x1"
+		},
+		"0x33469b22e9f636356c4160a87eb19df52b7412e8eac32a4a55ffe88ea8350788"
+	]
+}`
+
+func TestHexQuantities(t *testing.T) {
+	tests := []struct {
+		input string
+		want  uint64
+		errIs error
+	}{
+		{input: "0x0", want: 0},
+		{input: "0x5208", want: 21_000},
+		{input: "0xffffffffffffffff", want: ^uint64(0)},
+		{input: "5208", errIs: hexutil.ErrMissingPrefix},
+		{input: "0x", errIs: hexutil.ErrEmptyNumber},
+		{input: "0x05", errIs: hexutil.ErrLeadingZero},
+		{input: "0xg", errIs: hexutil.ErrSyntax},
+		{input: "0x10000000000000000", errIs: hexutil.ErrUint64Range},
+	}
+
+	for _, tt := range tests {
+		got, err := hexutil.DecodeUint64(tt.input)
+		if tt.errIs != nil {
+			if !errors.Is(err, tt.errIs) {
+				t.Errorf("%s: expected %v, got %v", tt.input, tt.errIs, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: expected %d, got %d (%v)", tt.input, tt.want, got, err)
+		}
+		if enc := hexutil.EncodeUint64(got); enc != tt.input {
+			t.Errorf("%d: expected encoding %s, got %s", got, tt.input, enc)
+		}
+	}
+
+	max := "0x" + strings.Repeat("f", 64)
+	if v, err := hexutil.DecodeUint256(max); err != nil || v != uint256.Max || hexutil.EncodeUint256(v) != max {
+		t.Errorf("expected 2^256-1 round trip, got %s (%v)", v, err)
+	}
+	if _, err := hexutil.DecodeUint256("0x1" + strings.Repeat("0", 64)); !errors.Is(err, hexutil.ErrUint256Range) {
+		t.Errorf("expected ErrUint256Range, got %v", err)
+	}
+	if _, err := hexutil.Decode("0xabc"); !errors.Is(err, hexutil.ErrOddLength) {
+		t.Errorf("expected ErrOddLength, got %v", err)
+	}
+}
+
+func TestUnmarshalRPCBlock(t *testing.T) {
+	var block types.Block
+	if err := json.Unmarshal([]byte(rpcBlock), &block); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if block.Number != 12_965_000 || block.GasLimit != 29_999_972 || block.GasUsed != 42_000 || block.Timestamp != 0x610bdaa4 {
+		t.Errorf("unexpected header fields: %+v", block)
+	}
+	if block.BaseFee != uint256.NewInt(1_000_000_000) {
+		t.Errorf("expected base fee 1 gwei, got %s", block.BaseFee)
+	}
+	if block.Miner != types.HexToAddress("0x7777788200b672a42421017f65ede4fc759564c8") {
+		t.Errorf("unexpected miner %s", block.Miner)
+	}
+
+	// The hash-only entry has no body and is skipped
+	if len(block.Transactions) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(block.Transactions))
+	}
+
+	legacy := block.Transactions[0]
+	if legacy.Type != types.LegacyTxType || legacy.ChainID != 1 || legacy.GasPrice != uint256.NewInt(20_000_000_000) {
+		t.Errorf("unexpected legacy transaction: %+v", legacy)
+	}
+	if sender, err := legacy.Sender(); err != nil || sender != legacy.From {
+		t.Errorf("expected sender %s, got %s (%v)", legacy.From, sender, err)
+	}
+
+	dynamic := block.Transactions[1]
+	if dynamic.Type != types.DynamicFeeTxType || dynamic.To != nil || dynamic.MaxFeePerGas != uint256.NewInt(50_000_000_000) {
+		t.Errorf("unexpected type-2 transaction: %+v", dynamic)
+	}
+	if !dynamic.GasPrice.IsZero() {
+		t.Error("the reported effective gasPrice should not populate GasPrice on a type-2 transaction")
+	}
+	if len(dynamic.AccessList) != 1 || string(dynamic.Data) != "\xca\xfe" {
+		t.Errorf("unexpected access list or input: %+v", dynamic)
+	}
+}
+
+func TestTransactionJSONRoundTrip(t *testing.T) {
+	txs := []*types.Transaction{
+		{Type: types.LegacyTxType, ChainID: 1, Nonce: 3, GasPrice: uint256.NewInt(7), GasLimit: 21_000, To: &bob, Value: uint256.NewInt(1)},
+		{Type: types.AccessListTxType, ChainID: 1, GasPrice: uint256.NewInt(7), GasLimit: 30_000, To: &bob, AccessList: testAccessList},
+		{Type: types.DynamicFeeTxType, ChainID: 1, MaxPriorityFeePerGas: uint256.NewInt(1), MaxFeePerGas: uint256.Max, GasLimit: 21_000, Data: []byte{1}},
+		newBlobTx(4, 2),
+	}
+
+	for _, tx := range txs {
+		signTx(t, tx, aliceKey)
+		enc, err := json.Marshal(tx)
+		if err != nil {
+			t.Fatalf("type %d: marshal: %v", tx.Type, err)
+		}
+
+		var dec types.Transaction
+		if err := json.Unmarshal(enc, &dec); err != nil {
+			t.Fatalf("type %d: unmarshal: %v\n%s", tx.Type, err, enc)
+		}
+		if dec.Hash() != tx.Hash() || dec.From != tx.From {
+			t.Errorf("type %d: transaction changed across JSON round trip\n%s", tx.Type, enc)
+		}
+	}
+
+	enc, _ := json.Marshal(txs[2])
+	for _, field := range []string{`"maxFeePerGas":"0x` + strings.Repeat("f", 64) + `"`, `"gas":"0x5208"`, `"input":"0x01"`, `"to":null`} {
+		if !strings.Contains(string(enc), field) {
+			t.Errorf("expected %s in %s", field, enc)
+		}
+	}
+}
+
+func TestTransactionJSONMissingField(t *testing.T) {
+	var tx types.Transaction
+	err := json.Unmarshal([]byte(`{"type":"0x2","chainId":"0x1","nonce":"0x0","gas":"0x5208","value":"0x0","input":"0x","v":"0x0","r":"0x0","s":"0x0"}`), &tx)
+	if !errors.Is(err, types.ErrMalformedTransaction) || !strings.Contains(err.Error(), "maxPriorityFeePerGas") {
+		t.Errorf("expected missing maxPriorityFeePerGas, got %v", err)
+	}
+
+	err = json.Unmarshal([]byte(`{"type":"0x7"}`), &tx)
+	if !errors.Is(err, types.ErrTxTypeNotSupported) {
+		t.Errorf("expected ErrTxTypeNotSupported, got %v", err)
+	}
+
+	var block types.Block
+	if err := json.Unmarshal([]byte(`{"number":"0x1"}`), &block); !errors.Is(err, types.ErrMalformedBlock) {
+		t.Errorf("expected ErrMalformedBlock, got %v", err)
+	}
+}
+
+func TestBlockJSONRoundTrip(t *testing.T) {
+	block := types.NewBlock(42, types.BytesToHash([]byte("parent")), 30_000_000, uint256.NewInt(1_000_000_000), miner)
+	block.Timestamp = 1_700_000_000
+	if err := block.AddTransaction(signTx(t, newBlobTx(0, 1), aliceKey)); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	enc, err := json.Marshal(block)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(enc), `"baseFeePerGas":"0x3b9aca00"`) || !strings.Contains(string(enc), `"hash":"`+block.Hash().Hex()+`"`) {
+		t.Errorf("unexpected encoding %s", enc)
+	}
+
+	var dec types.Block
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if dec.Hash() != block.Hash() || len(dec.Transactions) != 1 || dec.Transactions[0].Hash() != block.Transactions[0].Hash() {
+		t.Errorf("block changed across JSON round trip\n%s", enc)
+	}
+}
+
+func TestBlockJSONOmitsInactiveForkFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		block   *types.Block
+		present []string
+		absent  []string
+	}{
+		{"pre-London", types.NewBlock(constants.ForkBlockNumber-1, types.Hash{}, 15_000_000, uint256.NewInt(0), miner), nil, []string{"baseFeePerGas", "blobGasUsed", "excessBlobGas"}},
+		{"London", types.NewBlock(constants.ForkBlockNumber, types.Hash{}, 30_000_000, uint256.NewInt(constants.InitialBaseFee), miner), []string{"baseFeePerGas"}, []string{"blobGasUsed", "excessBlobGas"}},
+		{"no blob gas", types.NewBlock(19_426_587, types.Hash{}, 30_000_000, uint256.NewInt(constants.InitialBaseFee), miner), []string{"baseFeePerGas"}, []string{"blobGasUsed", "excessBlobGas"}},
+		{"excess blob gas only", &types.Block{Number: 19_426_588, GasLimit: 30_000_000, BaseFee: uint256.NewInt(constants.InitialBaseFee), Miner: miner, ExcessBlobGas: 131_072}, []string{`"blobGasUsed":"0x0"`, `"excessBlobGas":"0x20000"`}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, err := json.Marshal(tt.block)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			for _, field := range tt.present {
+				if !strings.Contains(string(enc), field) {
+					t.Errorf("missing %s in %s", field, enc)
+				}
+			}
+			for _, field := range tt.absent {
+				if strings.Contains(string(enc), field) {
+					t.Errorf("unexpected %s in %s", field, enc)
+				}
+			}
+
+			var dec types.Block
+			if err := json.Unmarshal(enc, &dec); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if dec.Hash() != tt.block.Hash() {
+				t.Errorf("block changed across JSON round trip\n%s", enc)
+			}
+		})
+	}
+}
+
+func TestExecutionResultJSON(t *testing.T) {
+	result := &executor.ExecutionResult{
+		GasUsed:       21_000,
+		BaseFeeAmount: uint256.NewInt(21_000_000_000_000),
+		TipAmount:     uint256.NewInt(42),
+		Success:       true,
+	}
+
+	enc, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `{"status":"0x1","gasUsed":"0x5208","baseFeeAmount":"0x1319718a5000","blobFeeAmount":"0x0","tipAmount":"0x2a"}`
+	if string(enc) != want {
+		t.Errorf("unexpected encoding\nhave %s\nwant %s", enc, want)
+	}
+
+	failed := &executor.ExecutionResult{Error: types.ErrInsufficientFunds}
+	enc, _ = json.Marshal(failed)
+	var dec executor.ExecutionResult
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if dec.Success || dec.Error == nil || dec.Error.Error() != types.ErrInsufficientFunds.Error() {
+		t.Errorf("unexpected decoded failure: %+v", dec)
+	}
+}
@@ -0,0 +1,94 @@
+package test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// londonHeaders returns blocks 12,964,998 to 12,965,001 from a header fixture: two
+// pre-London blocks, the fork block and the block after it
+func londonHeaders(t *testing.T, path string) (grandparent, preFork, fork, postFork *types.Block) {
+	t.Helper()
+	byNumber := make(map[uint64]*types.Block)
+	for _, block := range loadFixture(t, path).Blocks() {
+		byNumber[block.Number] = block
+	}
+	headers := make([]*types.Block, 4)
+	for i := range headers {
+		number := constants.ForkBlockNumber - 2 + uint64(i)
+		if headers[i] = byNumber[number]; headers[i] == nil {
+			t.Fatalf("%s is missing block %d", path, number)
+		}
+	}
+	return headers[0], headers[1], headers[2], headers[3]
+}
+
+// The rule tests only need a well-formed transition, so they use the synthetic fixture
+func TestLondonTransitionGasLimit(t *testing.T) {
+	_, preFork, fork, _ := londonHeaders(t, "testdata/synthetic_headers.json")
+
+	// Keeping the pre-fork limit is a 50% drop from the elasticity-adjusted parent limit
+	fork.GasLimit = preFork.GasLimit
+	fork.GasUsed = 0
+	err := validator.ValidateBlock(constants.MainnetConfig, fork, preFork, testNow)
+	var limitErr *validator.GasLimitError
+	if !errors.As(err, &limitErr) || limitErr.ParentLimit != 2*preFork.GasLimit {
+		t.Fatalf("expected GasLimitError against the doubled parent limit, got %v", err)
+	}
+
+	// The 1/1024 bound still applies around the adjusted limit
+	fork.GasLimit = 2*preFork.GasLimit + 2*preFork.GasLimit/constants.GasLimitBoundDivisor + 1
+	if err := validator.ValidateBlock(constants.MainnetConfig, fork, preFork, testNow); !errors.Is(err, validator.ErrGasLimitOutOfBounds) {
+		t.Errorf("expected ErrGasLimitOutOfBounds, got %v", err)
+	}
+
+	// Only the fork block is adjusted: doubling again afterwards is rejected
+	_, _, fork, postFork := londonHeaders(t, "testdata/synthetic_headers.json")
+	postFork.GasLimit = 2 * fork.GasLimit
+	if err := validator.ValidateBlock(constants.MainnetConfig, postFork, fork, testNow); !errors.Is(err, validator.ErrGasLimitOutOfBounds) {
+		t.Errorf("expected ErrGasLimitOutOfBounds after the fork, got %v", err)
+	}
+}
+
+func TestPreLondonBlocksHaveNoBaseFee(t *testing.T) {
+	grandparent, preFork, _, _ := londonHeaders(t, "testdata/synthetic_headers.json")
+
+	if got := basefee.Calculate(constants.MainnetConfig, grandparent); !got.IsZero() {
+		t.Errorf("expected no base fee before the fork, got %s", got)
+	}
+	if err := validator.ValidateBlock(constants.MainnetConfig, preFork, grandparent, testNow); err != nil {
+		t.Errorf("pre-fork block: %v", err)
+	}
+
+	preFork.BaseFee = uint256.NewInt(1)
+	err := validator.ValidateBlock(constants.MainnetConfig, preFork, grandparent, testNow)
+	var feeErr *validator.BaseFeeError
+	if !errors.As(err, &feeErr) || !feeErr.Expected.IsZero() {
+		t.Errorf("expected BaseFeeError with expected 0, got %v", err)
+	}
+}
+
+func TestGasLimitBoundsOverflow(t *testing.T) {
+	// Doubling the parent limit on the fork block would wrap around
+	parent := &types.Block{Number: constants.ForkBlockNumber - 1, GasLimit: math.MaxUint64/2 + 1, Miner: miner, Timestamp: 1}
+	fork := &types.Block{Number: constants.ForkBlockNumber, ParentHash: parent.Hash(), GasLimit: parent.GasLimit, BaseFee: uint256.NewInt(constants.InitialBaseFee), Miner: miner, Timestamp: 2}
+	err := validator.ValidateBlock(constants.MainnetConfig, fork, parent, testNow)
+	var limitErr *validator.GasLimitError
+	if !errors.As(err, &limitErr) || !errors.Is(err, validator.ErrGasLimitOutOfBounds) || !errors.Is(err, types.ErrGasUintOverflow) {
+		t.Errorf("expected GasLimitError wrapping ErrGasUintOverflow on the fork block, got %v", err)
+	}
+
+	// So would the 1/1024 margin above a near-maximal limit
+	parent = &types.Block{Number: constants.ForkBlockNumber, GasLimit: math.MaxUint64 - 1, BaseFee: uint256.NewInt(constants.InitialBaseFee), Miner: miner, Timestamp: 1}
+	child := &types.Block{Number: parent.Number + 1, ParentHash: parent.Hash(), GasLimit: parent.GasLimit, Miner: miner, Timestamp: 2}
+	if err := validator.ValidateBlock(constants.MainnetConfig, child, parent, testNow); !errors.As(err, &limitErr) || !errors.Is(err, types.ErrGasUintOverflow) {
+		t.Errorf("expected GasLimitError wrapping ErrGasUintOverflow, got %v", err)
+	}
+}
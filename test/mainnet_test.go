@@ -0,0 +1,74 @@
+//go:build mainnet
+
+// Tests against recorded mainnet headers. They need test/testdata/mainnet_headers.json,
+// captured with cmd/fetchheaders from a node with JSON-RPC access, and run with
+//
+//	go test -tags mainnet ./test
+package test
+
+import (
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// Headers captured from a mainnet archive node with
+//
+//	go run ./cmd/fetchheaders -rpc $RPC_URL -from 12964990 -to 12965500 > test/testdata/mainnet_headers.json
+func TestMainnetConformance(t *testing.T) {
+	fixture := loadFixture(t, "testdata/mainnet_headers.json")
+	if err := fixture.Verify(); err != nil {
+		t.Error(err)
+	}
+
+	// The range must exercise every branch of the rule, not just steady blocks
+	coverage := fixtureCoverage(constants.MainnetConfig, fixture.Blocks())
+	if !coverage.fork {
+		t.Errorf("fixture does not contain London fork block %d", constants.ForkBlockNumber)
+	}
+	if coverage.increaseRun < 2 {
+		t.Errorf("fixture has no run of maximum base fee increases (longest %d)", coverage.increaseRun)
+	}
+	if coverage.decreaseRun < 2 {
+		t.Errorf("fixture has no run of maximum base fee decreases (longest %d)", coverage.decreaseRun)
+	}
+	if coverage.gasLimitChanges == 0 {
+		t.Error("fixture has no gas limit changes after the fork")
+	}
+}
+
+// The London transition as recorded on mainnet
+func TestLondonTransition(t *testing.T) {
+	_, preFork, fork, postFork := londonHeaders(t, "testdata/mainnet_headers.json")
+
+	// Recorded baseFeePerGas: none before the fork, 1 gwei on the fork block
+	if !preFork.BaseFee.IsZero() {
+		t.Errorf("expected block %d to have no base fee, got %s", preFork.Number, preFork.BaseFee)
+	}
+	if fork.BaseFee != uint256.NewInt(1_000_000_000) {
+		t.Errorf("expected block %d to record 1 gwei, got %s", fork.Number, fork.BaseFee)
+	}
+	if got := basefee.Calculate(constants.MainnetConfig, fork); got != postFork.BaseFee {
+		t.Errorf("expected block %d to record %s, got %s", postFork.Number, got, postFork.BaseFee)
+	}
+
+	if err := validator.ValidateBlock(constants.MainnetConfig, fork, preFork, testNow); err != nil {
+		t.Errorf("fork block: %v", err)
+	}
+	if err := validator.ValidateBlock(constants.MainnetConfig, postFork, fork, testNow); err != nil {
+		t.Errorf("block after the fork: %v", err)
+	}
+
+	if got := basefee.Calculate(constants.MainnetConfig, preFork); got != uint256.NewInt(constants.InitialBaseFee) {
+		t.Errorf("expected the initial base fee on the fork block, got %s", got)
+	}
+	if got := preFork.GasTarget(constants.MainnetConfig); got != preFork.GasLimit {
+		t.Errorf("expected the pre-London target to be the whole limit, got %d", got)
+	}
+	if got := fork.GasTarget(constants.MainnetConfig); got != preFork.GasLimit {
+		t.Errorf("expected the fork block target to equal the old limit, got %d", got)
+	}
+}
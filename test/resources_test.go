@@ -0,0 +1,364 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+// multidimensionalConfig is mainnet with the default per-resource fee market
+func multidimensionalConfig() *constants.ChainConfig {
+	config := *constants.MainnetConfig
+	config.Resources = &constants.DefaultResourceConfig
+	return &config
+}
+
+// resourceParent returns a post-London block with every resource base fee at fee
+func resourceParent(fee uint64, gasUsed types.ResourceVector) *types.Block {
+	parent := &types.Block{
+		Number:          200_000_000,
+		GasLimit:        30_000_000,
+		BaseFee:         uint256.NewInt(fee),
+		ResourceGasUsed: gasUsed,
+	}
+	for r := range constants.NumResources {
+		parent.ResourceBaseFees[r] = uint256.NewInt(fee)
+	}
+	return parent
+}
+
+func TestCalculateResourceBaseFees(t *testing.T) {
+	config := multidimensionalConfig()
+
+	// Each resource moves independently: execution full, calldata empty, blob at target
+	parent := resourceParent(1_000_000_000, types.ResourceVector{30_000_000, 0, constants.TargetBlobGasPerBlock})
+	fees := basefee.CalculateResourceBaseFees(config, parent)
+	want := [constants.NumResources]uint64{1_125_000_000, 875_000_000, 1_000_000_000}
+	for r := range constants.NumResources {
+		if fees[r] != uint256.NewInt(want[r]) {
+			t.Errorf("%s: expected %d, got %s", constants.Resource(r), want[r], fees[r])
+		}
+	}
+
+	// The fork block starts every resource at its initial base fee
+	preFork := &types.Block{Number: config.LondonBlock - 1, GasLimit: 15_000_000}
+	fees = basefee.CalculateResourceBaseFees(config, preFork)
+	for r, params := range config.Resources {
+		if fees[r] != params.InitialBaseFee {
+			t.Errorf("fork %s: expected %d, got %s", constants.Resource(r), params.InitialBaseFee, fees[r])
+		}
+	}
+
+	// Single-dimensional chains have no resource base fees
+	if fees := basefee.CalculateResourceBaseFees(constants.MainnetConfig, parent); fees != (types.FeeVector{}) {
+		t.Errorf("expected no resource base fees without a resource config, got %v", fees)
+	}
+}
+
+func TestTransactionResourceGas(t *testing.T) {
+	tx := &types.Transaction{
+		Type:         types.DynamicFeeTxType,
+		MaxFeePerGas: uint256.NewInt(2_000_000_000),
+		GasLimit:     50_000,
+		Data:         bytes.Repeat([]byte{1}, 100),
+	}
+
+	gas, err := tx.ResourceGasLimit()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gas != (types.ResourceVector{48_400, 1_600, 0}) {
+		t.Errorf("expected [48400 1600 0], got %v", gas)
+	}
+
+	// Calldata priced above the cap is rejected even though execution is covered
+	fees := types.FeeVector{uint256.NewInt(1_000_000_000), uint256.NewInt(3_000_000_000), uint256.NewInt(1)}
+	err = tx.ValidateResourceFees(fees)
+	var capErr *types.ResourceFeeCapError
+	if !errors.As(err, &capErr) || capErr.Resource != constants.CalldataResource {
+		t.Fatalf("expected calldata ResourceFeeCapError, got %v", err)
+	}
+
+	// An unused resource is not checked
+	tx.Data = nil
+	if err := tx.ValidateResourceFees(fees); err != nil {
+		t.Errorf("expected no error without calldata, got %v", err)
+	}
+}
+
+func TestExecuteMultidimensionalBlock(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+
+	block := &types.Block{
+		Number:           200_000_001,
+		GasLimit:         30_000_000,
+		BaseFee:          uint256.NewInt(1_000),
+		ResourceBaseFees: types.FeeVector{uint256.NewInt(1_000), uint256.NewInt(300), uint256.NewInt(7)},
+		Miner:            miner,
+	}
+	tx := newBlobTx(0, 1)
+	tx.From = alice
+	tx.Data = bytes.Repeat([]byte{1}, 10)
+	tx.GasLimit = 30_000
+
+	result := executor.ExecuteTransaction(tx, block, state)
+	if !result.Success {
+		t.Fatalf("transaction should succeed, got error: %v", result.Error)
+	}
+
+	// 21000 execution gas, 160 calldata gas, one blob
+	if result.ResourceGasUsed != (types.ResourceVector{21_000, 160, constants.BlobGasPerBlob}) {
+		t.Errorf("expected resource gas used [21000 160 %d], got %v", constants.BlobGasPerBlob, result.ResourceGasUsed)
+	}
+	want := [constants.NumResources]uint64{21_000 * 1_000, 160 * 300, constants.BlobGasPerBlob * 7}
+	for r := range constants.NumResources {
+		if result.ResourceFeeAmounts[r] != uint256.NewInt(want[r]) {
+			t.Errorf("%s: expected burn %d, got %s", constants.Resource(r), want[r], result.ResourceFeeAmounts[r])
+		}
+	}
+	if result.BaseFeeAmount != uint256.NewInt(want[0]+want[1]) || result.BlobFeeAmount != uint256.NewInt(want[2]) {
+		t.Errorf("unexpected burn totals: base %s, blob %s", result.BaseFeeAmount, result.BlobFeeAmount)
+	}
+
+	// Only execution gas pays the 1 gwei tip
+	if result.TipAmount != uint256.NewInt(21_000*1_000_000_000) {
+		t.Errorf("expected tip on execution gas only, got %s", result.TipAmount)
+	}
+
+	// The sender pays exactly the burn, the tip and the value
+	paid := uint256.NewInt(1_000_000_000_000_000).Sub(state.GetBalance(alice))
+	expected := result.BaseFeeAmount.Add(result.BlobFeeAmount).Add(result.TipAmount).Add(tx.Value)
+	if paid != expected {
+		t.Errorf("expected sender to pay %s, paid %s", expected, paid)
+	}
+}
+
+func TestValidateMultidimensionalBlock(t *testing.T) {
+	config := multidimensionalConfig()
+	parent := resourceParent(1_000_000_000, types.ResourceVector{15_000_000, 2_000_000, 0})
+
+	fees := basefee.CalculateResourceBaseFees(config, parent)
+	newBlock := func() *types.Block {
+		block := types.NewBlock(parent.Number+1, parent.Hash(), parent.GasLimit, fees[constants.ExecutionResource], miner)
+		block.Timestamp = parent.Timestamp + 12
+		block.ResourceBaseFees = fees
+		return block
+	}
+
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+	block := newBlock()
+	tx := &types.Transaction{
+		Type:         types.DynamicFeeTxType,
+		ChainID:      1,
+		From:         alice,
+		To:           &bob,
+		MaxFeePerGas: uint256.NewInt(2_000_000_000),
+		GasLimit:     100_000,
+		Data:         bytes.Repeat([]byte{1}, 1_000),
+	}
+	if err := block.AddTransaction(tx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results, err := executor.ExecuteBlock(block, state)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if err := executor.SealBlock(block, results); err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	// Execution gas is what the transaction used (37000 intrinsic less 16000 calldata), not its 84000 reservation
+	if block.ResourceGasUsed != (types.ResourceVector{21_000, 16_000, 0}) || block.GasUsed != 37_000 {
+		t.Fatalf("expected resource gas [21000 16000 0] and 37000 gas, got %v and %d", block.ResourceGasUsed, block.GasUsed)
+	}
+	if err := validator.ValidateBlock(config, block, parent, testNow); err != nil {
+		t.Errorf("expected valid block, got %v", err)
+	}
+	if err := validator.ValidateReceipts(block, results); err != nil {
+		t.Errorf("expected receipts to match, got %v", err)
+	}
+
+	// Execution gas is checked against the receipts
+	sealed := *block
+	sealed.ResourceGasUsed[constants.ExecutionResource] = 84_000
+	var gasErr *validator.ResourceGasUsedError
+	if err := validator.ValidateReceipts(&sealed, results); !errors.As(err, &gasErr) || gasErr.Resource != constants.ExecutionResource || gasErr.Expected != 21_000 {
+		t.Errorf("expected execution ResourceGasUsedError, got %v", err)
+	}
+
+	// Calldata base fee not following its own target
+	block = newBlock()
+	block.ResourceBaseFees[constants.CalldataResource] = parent.ResourceBaseFees[constants.CalldataResource]
+	if err := validator.ValidateBlock(config, block, parent, testNow); !errors.Is(err, validator.ErrBadResourceBaseFee) {
+		t.Errorf("expected ErrBadResourceBaseFee, got %v", err)
+	}
+
+	// Calldata above its per-block limit
+	block = newBlock()
+	for range 3 {
+		if err := block.AddTransaction(&types.Transaction{GasLimit: 1_000_000, Data: bytes.Repeat([]byte{1}, 50_000)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	var limitErr *validator.ResourceLimitError
+	if err := validator.ValidateBlock(config, block, parent, testNow); !errors.As(err, &limitErr) || limitErr.Resource != constants.CalldataResource {
+		t.Errorf("expected calldata ResourceLimitError, got %v", err)
+	}
+
+	// Calldata gas used not matching the transactions
+	block = newBlock()
+	block.ResourceGasUsed[constants.CalldataResource] = 1
+	if err := validator.ValidateBlock(config, block, parent, testNow); !errors.Is(err, validator.ErrBadResourceGasUsed) {
+		t.Errorf("expected ErrBadResourceGasUsed, got %v", err)
+	}
+}
+
+func TestMultidimensionalBlockJSON(t *testing.T) {
+	block := resourceParent(1_000_000_000, types.ResourceVector{12_000_000, 500_000, constants.BlobGasPerBlob})
+	block.Miner = miner
+	block.ResourceBaseFees[constants.CalldataResource] = uint256.NewInt(875_000_000)
+
+	enc, err := json.Marshal(block)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(enc), `"resourceGasUsed":["0xb71b00","0x7a120","0x20000"]`) ||
+		!strings.Contains(string(enc), `"resourceBaseFeesPerGas":["0x3b9aca00","0x342770c0","0x3b9aca00"]`) {
+		t.Errorf("missing resource vectors in %s", enc)
+	}
+
+	var dec types.Block
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if dec.ResourceGasUsed != block.ResourceGasUsed || dec.ResourceBaseFees != block.ResourceBaseFees || dec.Hash() != block.Hash() {
+		t.Errorf("resource vectors changed across JSON round trip\n%s", enc)
+	}
+
+	// Single-dimension blocks keep the plain JSON-RPC shape
+	enc, _ = json.Marshal(types.NewBlock(block.Number, block.ParentHash, block.GasLimit, block.BaseFee, miner))
+	if strings.Contains(string(enc), "resource") {
+		t.Errorf("unexpected resource fields in %s", enc)
+	}
+}
+
+// newResourceFeeTx returns a transfer carrying calldata and one blob with a fee cap per resource
+func newResourceFeeTx(caps types.FeeVector) *types.Transaction {
+	return &types.Transaction{
+		Type:                 types.ResourceFeeTxType,
+		ChainID:              1,
+		MaxPriorityFeePerGas: uint256.NewInt(100),
+		GasLimit:             30_000,
+		To:                   &bob,
+		Value:                uint256.NewInt(1_000),
+		Data:                 bytes.Repeat([]byte{1}, 10),
+		ResourceFeeCaps:      caps,
+		BlobHashes:           []types.Hash{blobHash(0)},
+	}
+}
+
+func TestResourceFeeTxEncoding(t *testing.T) {
+	tx := signTx(t, newResourceFeeTx(types.FeeVector{uint256.NewInt(5_000), uint256.NewInt(400), uint256.NewInt(10)}), aliceKey)
+
+	// The fee caps are signed: changing one changes the signing hash
+	changed := *tx
+	changed.ResourceFeeCaps[constants.CalldataResource] = uint256.NewInt(401)
+	if changed.SigningHash() == tx.SigningHash() {
+		t.Error("expected the calldata fee cap to be part of the signing hash")
+	}
+
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if raw[0] != types.ResourceFeeTxType {
+		t.Fatalf("expected type byte 0x04, got 0x%02x", raw[0])
+	}
+	decoded, err := types.DecodeTransaction(raw)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decoded.ResourceFeeCaps != tx.ResourceFeeCaps || decoded.Hash() != tx.Hash() {
+		t.Errorf("fee caps changed across RLP round trip: %v", decoded.ResourceFeeCaps)
+	}
+	if sender, err := decoded.Sender(); err != nil || sender != alice {
+		t.Errorf("expected sender %s, got %s (%v)", alice.Hex(), sender.Hex(), err)
+	}
+
+	enc, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("marshal JSON: %v", err)
+	}
+	if !strings.Contains(string(enc), `"maxFeesPerGas":["0x1388","0x190","0xa"]`) || strings.Contains(string(enc), `"maxFeePerGas"`) {
+		t.Errorf("unexpected encoding %s", enc)
+	}
+	var fromJSON types.Transaction
+	if err := json.Unmarshal(enc, &fromJSON); err != nil {
+		t.Fatalf("unmarshal JSON: %v", err)
+	}
+	if fromJSON.Hash() != tx.Hash() {
+		t.Errorf("transaction changed across JSON round trip\n%s", enc)
+	}
+}
+
+func TestExecuteResourceFeeTx(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+	block := &types.Block{
+		Number:           200_000_001,
+		GasLimit:         30_000_000,
+		BaseFee:          uint256.NewInt(1_000),
+		ResourceBaseFees: types.FeeVector{uint256.NewInt(1_000), uint256.NewInt(300), uint256.NewInt(7)},
+		Miner:            miner,
+	}
+
+	// The calldata cap is checked on its own, however high the execution cap
+	tx := newResourceFeeTx(types.FeeVector{uint256.NewInt(1_000_000), uint256.NewInt(200), uint256.NewInt(10)})
+	var capErr *types.ResourceFeeCapError
+	if err := tx.ValidateResourceFees(block.ResourceBaseFees); !errors.As(err, &capErr) || capErr.Resource != constants.CalldataResource {
+		t.Fatalf("expected calldata ResourceFeeCapError, got %v", err)
+	}
+
+	tx = newResourceFeeTx(types.FeeVector{uint256.NewInt(5_000), uint256.NewInt(400), uint256.NewInt(10)})
+	tx.From = alice
+
+	// Each resource's gas limit is prepaid at its own cap
+	maxCost, err := tx.MaxCost()
+	if err != nil {
+		t.Fatalf("max cost: %v", err)
+	}
+	if want := uint256.NewInt(29_840*5_000 + 160*400 + constants.BlobGasPerBlob*10 + 1_000); maxCost != want {
+		t.Errorf("expected max cost %s, got %s", want, maxCost)
+	}
+
+	result := executor.ExecuteTransaction(tx, block, state)
+	if !result.Success {
+		t.Fatalf("transaction should succeed, got error: %v", result.Error)
+	}
+	if result.TipAmount != uint256.NewInt(21_000*100) {
+		t.Errorf("expected tip on execution gas only, got %s", result.TipAmount)
+	}
+	paid := uint256.NewInt(1_000_000_000_000_000).Sub(state.GetBalance(alice))
+	expected := result.BaseFeeAmount.Add(result.BlobFeeAmount).Add(result.TipAmount).Add(tx.Value)
+	if paid != expected || result.BaseFeeAmount != uint256.NewInt(21_000*1_000+160*300) {
+		t.Errorf("expected sender to pay %s, paid %s (burned %s)", expected, paid, result.BaseFeeAmount)
+	}
+
+	// Without per-resource base fees there is nothing to check the caps against
+	block.ResourceBaseFees = types.FeeVector{}
+	tx.Nonce = 1
+	if result := executor.ExecuteTransaction(tx, block, state); !errors.Is(result.Error, types.ErrNotMultidimensional) {
+		t.Errorf("expected ErrNotMultidimensional, got %v", result.Error)
+	}
+}
@@ -0,0 +1,48 @@
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/rlp"
+)
+
+func TestRLPEncoding(t *testing.T) {
+	lorem := "Lorem ipsum dolor sit amet, consectetur adipisicing elit"
+
+	tests := []struct {
+		name     string
+		encoded  []byte
+		expected string
+	}{
+		{name: "empty string", encoded: rlp.EncodeString(""), expected: "80"},
+		{name: "single byte", encoded: rlp.EncodeBytes([]byte{0x7f}), expected: "7f"},
+		{name: "single high byte", encoded: rlp.EncodeBytes([]byte{0x80}), expected: "8180"},
+		{name: "short string", encoded: rlp.EncodeString("dog"), expected: "83646f67"},
+		{name: "long string", encoded: rlp.EncodeString(lorem), expected: "b838" + hex.EncodeToString([]byte(lorem))},
+		{name: "zero", encoded: rlp.EncodeUint64(0), expected: "80"},
+		{name: "small int", encoded: rlp.EncodeUint64(15), expected: "0f"},
+		{name: "two byte int", encoded: rlp.EncodeUint64(1024), expected: "820400"},
+		{name: "empty list", encoded: rlp.EncodeList(), expected: "c0"},
+		{name: "string list", encoded: rlp.EncodeList(rlp.EncodeString("cat"), rlp.EncodeString("dog")), expected: "c88363617483646f67"},
+		{
+			name: "set theoretical three",
+			encoded: rlp.EncodeList(
+				rlp.EmptyList,
+				rlp.EncodeList(rlp.EmptyList),
+				rlp.EncodeList(rlp.EmptyList, rlp.EncodeList(rlp.EmptyList)),
+			),
+			expected: "c7c0c1c0c3c0c1c0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, _ := hex.DecodeString(tt.expected)
+			if !bytes.Equal(tt.encoded, expected) {
+				t.Errorf("expected %s, got %x", tt.expected, tt.encoded)
+			}
+		})
+	}
+}
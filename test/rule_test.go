@@ -0,0 +1,188 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func ruleParent(gasUsed uint64) *types.Block {
+	return &types.Block{
+		Number:   constants.ForkBlockNumber + 10,
+		GasLimit: 30_000_000,
+		GasUsed:  gasUsed,
+		BaseFee:  uint256.NewInt(1_000_000_000),
+	}
+}
+
+func TestBaseFeeRulesDirection(t *testing.T) {
+	for name, newRule := range basefee.Rules {
+		t.Run(name, func(t *testing.T) {
+			fee := uint256.NewInt(1_000_000_000)
+			if got := newRule().NextBaseFee(constants.MainnetConfig, ruleParent(15_000_000)); got != fee {
+				t.Errorf("at target: expected %s, got %s", fee, got)
+			}
+			if got := newRule().NextBaseFee(constants.MainnetConfig, ruleParent(30_000_000)); !got.Gt(fee) {
+				t.Errorf("full block: expected an increase, got %s", got)
+			}
+			if got := newRule().NextBaseFee(constants.MainnetConfig, ruleParent(0)); !got.Lt(fee) {
+				t.Errorf("empty block: expected a decrease, got %s", got)
+			}
+
+			// Every rule follows the London transition
+			preFork := &types.Block{Number: constants.ForkBlockNumber - 2, GasLimit: 15_000_000}
+			if got := newRule().NextBaseFee(constants.MainnetConfig, preFork); !got.IsZero() {
+				t.Errorf("pre-fork: expected no base fee, got %s", got)
+			}
+			preFork.Number++
+			if got := newRule().NextBaseFee(constants.MainnetConfig, preFork); got != uint256.NewInt(constants.InitialBaseFee) {
+				t.Errorf("fork block: expected the initial base fee, got %s", got)
+			}
+		})
+	}
+}
+
+func TestDefaultRuleMatchesCalculate(t *testing.T) {
+	for _, gasUsed := range []uint64{0, 7_500_000, 15_000_000, 22_500_000, 30_000_000} {
+		parent := ruleParent(gasUsed)
+		if got, want := basefee.DefaultRule.NextBaseFee(constants.MainnetConfig, parent), basefee.Calculate(constants.MainnetConfig, parent); got != want {
+			t.Errorf("gas used %d: expected %s, got %s", gasUsed, want, got)
+		}
+	}
+}
+
+func TestAIMDRule(t *testing.T) {
+	rule := &basefee.AIMDRule{Increase: uint256.NewInt(100), DecreaseDenominator: 2}
+	if got := rule.NextBaseFee(constants.MainnetConfig, ruleParent(30_000_000)); got != uint256.NewInt(1_000_000_100) {
+		t.Errorf("expected an additive increase of 100, got %s", got)
+	}
+	if got := rule.NextBaseFee(constants.MainnetConfig, ruleParent(1)); got != uint256.NewInt(500_000_000) {
+		t.Errorf("expected the fee to halve, got %s", got)
+	}
+}
+
+func TestExponentialRuleIsPathIndependent(t *testing.T) {
+	rule := basefee.ExponentialRule{}
+	up := rule.NextBaseFee(constants.MainnetConfig, ruleParent(30_000_000))
+
+	// e^(1/8): a full block raises the fee by ~13.3%
+	if up.Lt(uint256.NewInt(1_133_000_000)) || up.Gt(uint256.NewInt(1_134_000_000)) {
+		t.Errorf("expected ~1.133 gwei after a full block, got %s", up)
+	}
+
+	// An empty block cancels the excess of a full one
+	parent := ruleParent(0)
+	parent.BaseFee = up
+	down := rule.NextBaseFee(constants.MainnetConfig, parent)
+	if down.Lt(uint256.NewInt(999_999_990)) || down.Gt(uint256.NewInt(1_000_000_010)) {
+		t.Errorf("expected to return to ~1 gwei, got %s", down)
+	}
+}
+
+func TestStatefulRulesAdvanceOncePerBlock(t *testing.T) {
+	for _, rule := range []basefee.BaseFeeRule{basefee.NewPIDRule(0.125, 0.1, 0.05), basefee.NewMovingAverageRule(4)} {
+		parent := ruleParent(30_000_000)
+		first := rule.NextBaseFee(constants.MainnetConfig, parent)
+		if again := rule.NextBaseFee(constants.MainnetConfig, parent); again != first {
+			t.Errorf("%s: repeated call for the same parent changed the fee from %s to %s", rule.Name(), first, again)
+		}
+	}
+
+	// The integral term keeps pushing after the load returns to target
+	pid := basefee.NewPIDRule(0, 0.1, 0)
+	parent := ruleParent(30_000_000)
+	parent.BaseFee = pid.NextBaseFee(constants.MainnetConfig, parent)
+	parent.Number++
+	parent.GasUsed = 15_000_000
+	if got := pid.NextBaseFee(constants.MainnetConfig, parent); !got.Gt(parent.BaseFee) {
+		t.Errorf("pid: expected the integral to keep raising the fee, got %s from %s", got, parent.BaseFee)
+	}
+
+	// One full block in a window of four at-target blocks moves the fee by a quarter of the EIP-1559 step
+	ma := basefee.NewMovingAverageRule(4)
+	parent = ruleParent(15_000_000)
+	for range 3 {
+		ma.NextBaseFee(constants.MainnetConfig, parent)
+		parent.Number++
+	}
+	parent.GasUsed = 30_000_000
+	if got := ma.NextBaseFee(constants.MainnetConfig, parent); got != uint256.NewInt(1_031_250_000) {
+		t.Errorf("moving-average: expected 1031250000, got %s", got)
+	}
+}
+
+func TestValidateBlockWithRule(t *testing.T) {
+	rule := &basefee.AIMDRule{Increase: uint256.NewInt(1_000), DecreaseDenominator: 8}
+	parent := ruleParent(30_000_000)
+	block := types.NewBlock(parent.Number+1, parent.Hash(), 30_000_000, uint256.NewInt(1_000_001_000), miner)
+	block.Timestamp = parent.Timestamp + 12
+
+	if err := validator.ValidateBlockWithRule(constants.MainnetConfig, rule, block, parent, testNow); err != nil {
+		t.Errorf("expected the AIMD base fee to be valid under the AIMD rule, got %v", err)
+	}
+	if err := validator.ValidateBlock(constants.MainnetConfig, block, parent, testNow); !errors.Is(err, validator.ErrBadBaseFee) {
+		t.Errorf("expected ErrBadBaseFee under the EIP-1559 rule, got %v", err)
+	}
+}
+
+func TestTimeAwareRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		blockTime uint64
+		gasUsed   uint64
+		want      uint64
+	}{
+		// Regular slots behave exactly like EIP-1559
+		{name: "full block on time", blockTime: 12, gasUsed: 30_000_000, want: 1_125_000_000},
+		{name: "empty block on time", blockTime: 12, gasUsed: 0, want: 875_000_000},
+		// After a missed slot the target doubles, capped at 95% of the limit (28.5M)
+		{name: "full block after missed slot", blockTime: 24, gasUsed: 30_000_000, want: 1_012_500_000},
+		{name: "target block after missed slot", blockTime: 24, gasUsed: 15_000_000, want: 887_500_000},
+		// A fast block has a smaller target: 15M gas in 6s is over the 7.5M target
+		{name: "target block after a short slot", blockTime: 6, gasUsed: 15_000_000, want: 1_062_500_000},
+	}
+
+	shared := basefee.NewTimeAwareRule(constants.SecondsPerSlot)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := ruleParent(tt.gasUsed)
+			parent.Timestamp = 1_700_000_000
+			timestamp := parent.Timestamp + tt.blockTime
+
+			// The fee depends only on the headers, not on which instance computes it or what it saw before
+			fresh := basefee.NextBaseFee(basefee.NewTimeAwareRule(constants.SecondsPerSlot), constants.MainnetConfig, parent, timestamp)
+			if fresh != uint256.NewInt(tt.want) {
+				t.Errorf("expected %d, got %s", tt.want, fresh)
+			}
+			if got := basefee.NextBaseFee(shared, constants.MainnetConfig, parent, timestamp); got != fresh {
+				t.Errorf("a rule with history computed %s, a fresh one %s", got, fresh)
+			}
+		})
+	}
+
+	// Without a timestamp the rule assumes a regular slot
+	if got := basefee.NewTimeAwareRule(constants.SecondsPerSlot).NextBaseFee(constants.MainnetConfig, ruleParent(30_000_000)); got != uint256.NewInt(1_125_000_000) {
+		t.Errorf("expected the EIP-1559 fee one slot after the parent, got %s", got)
+	}
+}
+
+func TestValidateBlockWithTimeAwareRule(t *testing.T) {
+	parent := ruleParent(30_000_000)
+	parent.Miner, parent.Timestamp = miner, 1_700_000_000
+	block := types.NewBlock(parent.Number+1, parent.Hash(), parent.GasLimit, uint256.Zero, miner)
+	block.Timestamp = parent.Timestamp + 2*constants.SecondsPerSlot
+
+	// The producer and a validator with its own instance agree after a missed slot
+	block.BaseFee = basefee.NextBaseFee(basefee.NewTimeAwareRule(constants.SecondsPerSlot), constants.MainnetConfig, parent, block.Timestamp)
+	if block.BaseFee != uint256.NewInt(1_012_500_000) {
+		t.Fatalf("expected the damped increase after a missed slot, got %s", block.BaseFee)
+	}
+	if err := validator.ValidateBlockWithRule(constants.MainnetConfig, basefee.NewTimeAwareRule(constants.SecondsPerSlot), block, parent, testNow); err != nil {
+		t.Errorf("expected the block to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package test
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/crypto"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func TestPubkeyToAddress(t *testing.T) {
+	tests := []struct {
+		key     string
+		address string
+	}{
+		{key: "0x0000000000000000000000000000000000000000000000000000000000000001", address: "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"},
+		{key: "0x0000000000000000000000000000000000000000000000000000000000000002", address: "0x2B5AD5c4795c026514f8317c7a215E218DcCD6cF"},
+	}
+
+	for _, tt := range tests {
+		key := mustKey(tt.key)
+		if got := types.PubkeyToAddress(key.PublicKey).Hex(); got != tt.address {
+			t.Errorf("key %s: expected %s, got %s", tt.key, tt.address, got)
+		}
+	}
+}
+
+func TestSignDeterministic(t *testing.T) {
+	// Signing hash and signature from the EIP-155 example transaction
+	key := mustKey("0x4646464646464646464646464646464646464646464646464646464646464646")
+	hash, _ := hex.DecodeString("daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53")
+
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	r, _ := new(big.Int).SetString("18515461264373351373200002665853028612451056578545711640558177340181847433846", 10)
+	s, _ := new(big.Int).SetString("46948507304638947509940763649030358759909902576025900602547168820602576006531", 10)
+	if new(big.Int).SetBytes(sig[:32]).Cmp(r) != 0 || new(big.Int).SetBytes(sig[32:64]).Cmp(s) != 0 || sig[64] != 0 {
+		t.Errorf("unexpected signature %x", sig)
+	}
+
+	pub, err := crypto.RecoverPubkey(hash, sig)
+	if err != nil {
+		t.Fatalf("recover: %v", err)
+	}
+	if types.PubkeyToAddress(*pub) != types.PubkeyToAddress(key.PublicKey) {
+		t.Error("recovered key does not match signer")
+	}
+}
+
+func TestTransactionSenderRecovery(t *testing.T) {
+	tx := signTx(t, &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		ChainID:              1,
+		Nonce:                42,
+		MaxPriorityFeePerGas: uint256.NewInt(2_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(30_000_000_000),
+		GasLimit:             21_000,
+		To:                   &bob,
+		Value:                uint256.NewInt(1),
+	}, aliceKey)
+
+	if tx.From != alice {
+		t.Errorf("Sign should set From to %s, got %s", alice, tx.From)
+	}
+
+	// The sender must survive the wire format, which does not carry From
+	enc, _ := tx.MarshalBinary()
+	decoded, err := types.DecodeTransaction(enc)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	sender, err := decoded.Sender()
+	if err != nil {
+		t.Fatalf("sender: %v", err)
+	}
+	if sender != alice {
+		t.Errorf("expected sender %s, got %s", alice, sender)
+	}
+}
+
+func TestValidateTransactionSignature(t *testing.T) {
+	header := &types.Block{BaseFee: uint256.NewInt(1_000_000_000)}
+
+	newTx := func() *types.Transaction {
+		return signTx(t, &types.Transaction{
+			Type:                 types.DynamicFeeTxType,
+			ChainID:              1,
+			MaxPriorityFeePerGas: uint256.NewInt(1_000_000_000),
+			MaxFeePerGas:         uint256.NewInt(2_000_000_000),
+			GasLimit:             21_000,
+			To:                   &bob,
+			Value:                uint256.NewInt(1_000),
+		}, aliceKey)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(tx *types.Transaction)
+		errIs  error
+	}{
+		{name: "valid", mutate: func(tx *types.Transaction) {}},
+		{
+			name:   "unsigned",
+			mutate: func(tx *types.Transaction) { tx.V, tx.R, tx.S = uint256.Zero, uint256.Zero, uint256.Zero },
+			errIs:  types.ErrInvalidSig,
+		},
+		{
+			name:   "claims another sender",
+			mutate: func(tx *types.Transaction) { tx.From = bob },
+			errIs:  types.ErrSenderMismatch,
+		},
+		{
+			name:   "tampered after signing",
+			mutate: func(tx *types.Transaction) { tx.Value = uint256.NewInt(1_000_000) },
+			errIs:  types.ErrSenderMismatch,
+		},
+		{
+			name: "high s",
+			mutate: func(tx *types.Transaction) {
+				// (r, n-s, v^1) is the malleable twin of a valid signature
+				tx.S = uint256.MustFromBig(crypto.Secp256k1N).Sub(tx.S)
+				tx.V = uint256.NewInt(tx.V.Uint64() ^ 1)
+			},
+			errIs: types.ErrInvalidSig,
+		},
+		{
+			name:   "invalid y-parity",
+			mutate: func(tx *types.Transaction) { tx.V = uint256.NewInt(27) },
+			errIs:  types.ErrInvalidSig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := types.NewState()
+			state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+			state.SetAccount(bob, types.NewAccount(bob, uint256.NewInt(1_000_000_000_000_000)))
+
+			tx := newTx()
+			tt.mutate(tx)
+
+			err := validator.ValidateTransaction(constants.MainnetConfig, tx, header, state)
+			if tt.errIs == nil {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.errIs) {
+				t.Errorf("expected %v, got %v", tt.errIs, err)
+			}
+		})
+	}
+}
+
+func TestHighSRecoversSameKeyWithoutLowSRule(t *testing.T) {
+	tx := signTx(t, &types.Transaction{Type: types.DynamicFeeTxType, ChainID: 1, GasLimit: 21_000, MaxFeePerGas: uint256.NewInt(1)}, aliceKey)
+	hash := tx.SigningHash()
+
+	// The curve-level recovery accepts the malleable twin; only the transaction rule rejects it
+	highS := new(big.Int).Sub(crypto.Secp256k1N, tx.S.ToBig())
+	sig := make([]byte, crypto.SignatureLength)
+	tx.R.ToBig().FillBytes(sig[:32])
+	highS.FillBytes(sig[32:64])
+	sig[64] = byte(tx.V.Uint64() ^ 1)
+
+	if crypto.ValidateSignatureValues(sig[64], tx.R.ToBig(), highS, true) {
+		t.Error("high s should fail the low-s check")
+	}
+
+	pub, err := crypto.RecoverPubkey(hash[:], sig)
+	if err != nil {
+		t.Fatalf("recover: %v", err)
+	}
+	if types.PubkeyToAddress(*pub) != alice {
+		t.Error("malleable signature should recover the same key")
+	}
+}
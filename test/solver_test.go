@@ -0,0 +1,117 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func solverParent(baseFee uint64) *types.Block {
+	return &types.Block{
+		Number:   200_000_000, // Post-London on every preset
+		GasLimit: 30_000_000,
+		GasUsed:  15_000_000,
+		BaseFee:  uint256.NewInt(baseFee),
+	}
+}
+
+func TestSolveGasScheduleUp(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *constants.ChainConfig
+		target uint64
+		blocks int
+	}{
+		// 1.125^19 < 10 < 1.125^20
+		{name: "mainnet 10x", config: constants.MainnetConfig, target: 10_000_000_000, blocks: 20},
+		// 1.02^116 < 10 < 1.02^117
+		{name: "optimism 10x", config: constants.OptimismConfig, target: 10_000_000_000, blocks: 117},
+		{name: "already there", config: constants.MainnetConfig, target: 1_000_000_000, blocks: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := solverParent(1_000_000_000)
+			parent.GasUsed = parent.GasTarget(tt.config)
+			target := uint256.NewInt(tt.target)
+			schedule, err := basefee.SolveGasSchedule(tt.config, parent, target, 1_000)
+			if err != nil {
+				t.Fatalf("solve: %v", err)
+			}
+			if schedule.Blocks() != tt.blocks || len(schedule.BaseFees) != tt.blocks+1 {
+				t.Fatalf("expected %d blocks, got %d", tt.blocks, schedule.Blocks())
+			}
+
+			fees := schedule.BaseFees
+			if fees[len(fees)-1].Lt(target) || (len(fees) > 1 && !fees[len(fees)-2].Lt(target)) {
+				t.Errorf("expected only the last fee to reach %s, got %v", target, fees[len(fees)-2:])
+			}
+			if tt.blocks == 0 {
+				return
+			}
+
+			// Replaying the schedule gives the same fees
+			replayed := basefee.CalculateForBlocks(tt.config, parent, append(schedule.GasUsed, 0))
+			for i, fee := range fees {
+				if replayed[i] != fee {
+					t.Errorf("block %d: schedule says %s, replay gives %s", i, fee, replayed[i])
+				}
+			}
+
+			for _, gasUsed := range schedule.GasUsed[:tt.blocks-1] {
+				if gasUsed != parent.GasLimit {
+					t.Errorf("expected full blocks before the last, got %d", gasUsed)
+				}
+			}
+
+			// One gas less in the last block falls short
+			last := &types.Block{
+				Number:   parent.Number + uint64(tt.blocks),
+				GasLimit: parent.GasLimit,
+				GasUsed:  schedule.GasUsed[tt.blocks-1] - 1,
+				BaseFee:  fees[tt.blocks-1],
+			}
+			if !basefee.Calculate(tt.config, last).Lt(target) {
+				t.Errorf("last block gas %d is not minimal", schedule.GasUsed[tt.blocks-1])
+			}
+		})
+	}
+}
+
+func TestSolveGasScheduleDown(t *testing.T) {
+	target := uint256.NewInt(1_000_000_000)
+	schedule, err := basefee.SolveGasSchedule(constants.MainnetConfig, solverParent(100_000_000_000), target, 1_000)
+	if err != nil {
+		t.Fatalf("solve: %v", err)
+	}
+
+	// (7/8)^34 > 1/100 > (7/8)^35
+	if schedule.Blocks() != 35 {
+		t.Fatalf("expected 35 blocks, got %d", schedule.Blocks())
+	}
+	for _, gasUsed := range schedule.GasUsed[:34] {
+		if gasUsed != 0 {
+			t.Errorf("expected empty blocks before the last, got %d", gasUsed)
+		}
+	}
+	if fee := schedule.BaseFees[35]; fee.Gt(target) || schedule.GasUsed[34] == 0 {
+		t.Errorf("expected the last block to use spare gas and land at or below %s, got %s with %d gas", target, fee, schedule.GasUsed[34])
+	}
+}
+
+func TestSolveGasScheduleUnreachable(t *testing.T) {
+	// An empty block cannot lower a fee below 8 wei
+	_, err := basefee.SolveGasSchedule(constants.MainnetConfig, solverParent(1_000), uint256.NewInt(5), 1_000)
+	if !errors.Is(err, basefee.ErrTargetUnreachable) {
+		t.Errorf("expected ErrTargetUnreachable, got %v", err)
+	}
+
+	_, err = basefee.SolveGasSchedule(constants.MainnetConfig, solverParent(1_000_000_000), uint256.NewInt(10_000_000_000), 19)
+	if !errors.Is(err, basefee.ErrTargetUnreachable) {
+		t.Errorf("expected ErrTargetUnreachable within 19 blocks, got %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func TestStateSnapshotRevert(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(100)))
+	account := state.GetAccount(alice)
+
+	outer := state.Snapshot()
+	if err := account.Deduct(uint256.NewInt(30)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	account.IncrementNonce()
+
+	inner := state.Snapshot()
+	if err := state.GetAccount(bob).Add(uint256.NewInt(30)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The inner revert drops only bob's account, which did not exist before
+	state.RevertToSnapshot(inner)
+	if state.Exist(bob) {
+		t.Error("expected bob's account to be removed")
+	}
+	if state.GetBalance(alice) != uint256.NewInt(70) || state.GetNonce(alice) != 1 {
+		t.Errorf("expected alice at 70 wei and nonce 1, got %s and %d", state.GetBalance(alice), state.GetNonce(alice))
+	}
+
+	state.RevertToSnapshot(outer)
+	if state.GetBalance(alice) != uint256.NewInt(100) || state.GetNonce(alice) != 0 {
+		t.Errorf("expected alice back at 100 wei and nonce 0, got %s and %d", state.GetBalance(alice), state.GetNonce(alice))
+	}
+
+	// Replacing an account is undone as well
+	snapshot := state.Snapshot()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.Zero))
+	state.RevertToSnapshot(snapshot)
+	if state.GetAccount(alice) != account {
+		t.Error("expected the original account to be restored")
+	}
+}
+
+func TestRevertToInvalidSnapshotPanics(t *testing.T) {
+	state := types.NewState()
+	first := state.Snapshot()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1)))
+	second := state.Snapshot()
+	state.RevertToSnapshot(first)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected reverting to a snapshot invalidated by an earlier revert to panic")
+		}
+	}()
+	state.RevertToSnapshot(second)
+}
+
+func TestExecuteTransactionRevertsPartialChanges(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+	// Crediting the value would overflow bob's balance, after alice has already paid
+	state.SetAccount(bob, types.NewAccount(bob, uint256.Max))
+
+	block := types.NewBlock(1, types.Hash{}, 30_000_000, uint256.NewInt(1_000_000_000), miner)
+	tx := &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		ChainID:              1,
+		From:                 alice,
+		To:                   &bob,
+		MaxPriorityFeePerGas: uint256.NewInt(1_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(2_000_000_000),
+		GasLimit:             21_000,
+		Value:                uint256.NewInt(1),
+	}
+
+	if result := executor.ExecuteTransaction(tx, block, state); result.Success {
+		t.Fatal("expected the transfer to fail")
+	}
+	if state.GetBalance(alice) != uint256.NewInt(1_000_000_000_000_000) || state.GetNonce(alice) != 0 {
+		t.Errorf("expected alice unchanged, got %s wei and nonce %d", state.GetBalance(alice), state.GetNonce(alice))
+	}
+	if state.Exist(miner) {
+		t.Error("expected no miner account to be left behind")
+	}
+}
+
+func TestExecuteBlockIsAtomic(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+
+	block := types.NewBlock(1, types.Hash{}, 30_000_000, uint256.NewInt(1_000_000_000), miner)
+	for nonce := range uint64(2) {
+		tx := &types.Transaction{
+			Type:                 types.DynamicFeeTxType,
+			ChainID:              1,
+			From:                 alice,
+			To:                   &bob,
+			Nonce:                nonce,
+			MaxPriorityFeePerGas: uint256.NewInt(1_000_000_000),
+			MaxFeePerGas:         uint256.NewInt(2_000_000_000),
+			GasLimit:             21_000,
+			Value:                uint256.NewInt(1_000),
+		}
+		if err := block.AddTransaction(tx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// The second transaction cannot pay its value
+	block.Transactions[1].Value = uint256.NewInt(2_000_000_000_000_000)
+
+	results, err := executor.ExecuteBlock(block, state)
+	if err == nil || len(results) != 2 || !results[0].Success {
+		t.Fatalf("expected the first transaction to succeed and the second to fail, got %v", err)
+	}
+
+	// The first transaction's transfer, fees and nonce are rolled back with the block
+	if state.GetBalance(alice) != uint256.NewInt(1_000_000_000_000_000) || state.GetNonce(alice) != 0 {
+		t.Errorf("expected alice unchanged, got %s wei and nonce %d", state.GetBalance(alice), state.GetNonce(alice))
+	}
+	if !state.GetBalance(bob).IsZero() || !state.GetBalance(miner).IsZero() {
+		t.Errorf("expected no transfer or tip to remain, got bob %s and miner %s", state.GetBalance(bob), state.GetBalance(miner))
+	}
+}
+
+func TestExecuteBlockRevertsRejectedBlock(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+	parent := &types.Block{Number: constants.ForkBlockNumber + 1, GasLimit: 30_000_000, GasUsed: 15_000_000, BaseFee: uint256.NewInt(1_000_000_000), Miner: miner}
+
+	// The block executes, but its base fee is not the one its parent sets
+	block := types.NewBlock(parent.Number+1, parent.Hash(), 30_000_000, uint256.NewInt(2_000_000_000), miner)
+	block.Timestamp = parent.Timestamp + 12
+	if err := block.AddTransaction(signTx(t, newTransfer(1), aliceKey)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	snapshot := state.Snapshot()
+	results, err := executor.ExecuteBlock(block, state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := executor.SealBlock(block, results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := validator.ValidateExecutedBlock(constants.MainnetConfig, basefee.DefaultRule, block, parent, testNow, results); !errors.Is(err, validator.ErrBadBaseFee) {
+		t.Fatalf("expected ErrBadBaseFee, got %v", err)
+	}
+
+	// ExecuteBlock does not commit, so the rejected block can still be rolled back
+	state.RevertToSnapshot(snapshot)
+	if state.GetNonce(alice) != 0 || state.GetBalance(alice) != uint256.NewInt(1_000_000_000_000_000) || state.Exist(bob) || state.Exist(miner) {
+		t.Errorf("expected the rejected block to be reverted, got nonce %d and balance %s", state.GetNonce(alice), state.GetBalance(alice))
+	}
+
+	// The same transaction in a valid block is kept once the caller commits
+	block = types.NewBlock(parent.Number+1, parent.Hash(), 30_000_000, uint256.NewInt(1_000_000_000), miner)
+	block.Timestamp = parent.Timestamp + 12
+	if err := block.AddTransaction(signTx(t, newTransfer(1), aliceKey)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results, err = executor.ExecuteBlock(block, state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := executor.SealBlock(block, results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := validator.ValidateExecutedBlock(constants.MainnetConfig, basefee.DefaultRule, block, parent, testNow, results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state.Commit()
+	if id := state.Snapshot(); id != 0 {
+		t.Errorf("expected an empty journal after commit, got %d changes", id)
+	}
+	if state.GetNonce(alice) != 1 || state.GetBalance(bob) != uint256.NewInt(1_000) {
+		t.Errorf("expected the committed transfer, got nonce %d and bob %s", state.GetNonce(alice), state.GetBalance(bob))
+	}
+}
@@ -0,0 +1,159 @@
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/rlp"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func TestDynamicFeeTxEncoding(t *testing.T) {
+	to := types.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	tx := &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		ChainID:              1,
+		MaxPriorityFeePerGas: uint256.NewInt(1),
+		MaxFeePerGas:         uint256.NewInt(2),
+		GasLimit:             21_000,
+		To:                   &to,
+		R:                    uint256.NewInt(1),
+		S:                    uint256.NewInt(1),
+	}
+
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	expected := "02e201800102825208940000000000000000000000000000000000000b0b8080c0800101"
+	if hex.EncodeToString(enc) != expected {
+		t.Errorf("expected %s, got %x", expected, enc)
+	}
+}
+
+func TestDynamicFeeTxRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		tx   *types.Transaction
+	}{
+		{
+			name: "transfer",
+			tx: &types.Transaction{
+				Type:                 types.DynamicFeeTxType,
+				ChainID:              1,
+				Nonce:                7,
+				MaxPriorityFeePerGas: uint256.NewInt(2_000_000_000),
+				MaxFeePerGas:         uint256.NewInt(50_000_000_000),
+				GasLimit:             21_000,
+				To:                   &bob,
+				Value:                uint256.MustFromDecimal("1000000000000000000000"),
+				V:                    uint256.NewInt(1),
+				R:                    uint256.MustFromDecimal("115792089237316195423570985008687907852837564279074904382605163141518161494336"),
+				S:                    uint256.NewInt(12345),
+			},
+		},
+		{
+			name: "contract creation with long data",
+			tx: &types.Transaction{
+				Type:                 types.DynamicFeeTxType,
+				ChainID:              11155111,
+				MaxPriorityFeePerGas: uint256.Zero,
+				MaxFeePerGas:         uint256.NewInt(1),
+				GasLimit:             1_000_000,
+				Data:                 bytes.Repeat([]byte{0x60, 0x00}, 300),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, err := tt.tx.MarshalBinary()
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if enc[0] != types.DynamicFeeTxType {
+				t.Fatalf("expected type byte 0x02, got 0x%02x", enc[0])
+			}
+
+			dec, err := types.DecodeTransaction(enc)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if !reflect.DeepEqual(dec, tt.tx) {
+				t.Errorf("round trip mismatch:\n got %+v\nwant %+v", dec, tt.tx)
+			}
+			if dec.Hash() != tt.tx.Hash() {
+				t.Error("hash changed across round trip")
+			}
+		})
+	}
+}
+
+func TestDecodeMalformedTransaction(t *testing.T) {
+	valid, _ := (&types.Transaction{Type: types.DynamicFeeTxType, ChainID: 1, GasLimit: 21_000, To: &bob}).MarshalBinary()
+
+	// Rebuild a payload from valid fields with one field replaced
+	withField := func(i int, field []byte) []byte {
+		elems, err := rlp.ListElements(valid[1:])
+		if err != nil {
+			t.Fatalf("split: %v", err)
+		}
+		elems[i] = field
+		return append([]byte{types.DynamicFeeTxType}, rlp.EncodeList(elems...)...)
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		errIs error
+	}{
+		{name: "empty", input: nil, errIs: types.ErrMalformedTransaction},
+		{name: "typed fields without type byte", input: valid[1:], errIs: types.ErrMalformedTransaction},
+		{name: "unknown type", input: append([]byte{0x05}, valid[1:]...), errIs: types.ErrTxTypeNotSupported},
+		{name: "truncated", input: valid[:len(valid)-1], errIs: rlp.ErrUnexpectedEnd},
+		{name: "trailing bytes", input: append(append([]byte{}, valid...), 0x80), errIs: rlp.ErrTrailingData},
+		{name: "payload not a list", input: append([]byte{types.DynamicFeeTxType}, rlp.EncodeString("tx")...), errIs: rlp.ErrExpectedList},
+		{name: "too few fields", input: append([]byte{types.DynamicFeeTxType}, rlp.EncodeList(rlp.EncodeUint64(1))...), errIs: types.ErrMalformedTransaction},
+		{name: "nonce with leading zero", input: withField(1, []byte{0x82, 0x00, 0x01}), errIs: rlp.ErrCanonInt},
+		{name: "non-canonical single byte", input: withField(1, []byte{0x81, 0x05}), errIs: rlp.ErrCanonSize},
+		{name: "gas limit too large", input: withField(4, rlp.EncodeBytes(bytes.Repeat([]byte{0xff}, 9))), errIs: rlp.ErrUint64Range},
+		{name: "value too large", input: withField(6, rlp.EncodeBytes(bytes.Repeat([]byte{0xff}, 33))), errIs: uint256.ErrOverflow},
+		{name: "short recipient", input: withField(5, rlp.EncodeBytes([]byte{0x01, 0x02})), errIs: types.ErrInvalidAddress},
+		{name: "list as nonce", input: withField(1, rlp.EmptyList), errIs: rlp.ErrExpectedString},
+		{name: "string as access list", input: withField(8, rlp.EmptyString), errIs: rlp.ErrExpectedList},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := types.DecodeTransaction(tt.input)
+			if !errors.Is(err, tt.errIs) {
+				t.Errorf("expected %v, got %v", tt.errIs, err)
+			}
+		})
+	}
+}
+
+func TestRLPDecodeCanonical(t *testing.T) {
+	// 56-byte string using the long form decodes; a 3-byte string using it does not
+	long := rlp.EncodeBytes(bytes.Repeat([]byte{'a'}, 56))
+	if content, rest, err := rlp.SplitString(long); err != nil || len(content) != 56 || len(rest) != 0 {
+		t.Errorf("long string: got %d bytes, rest %d, err %v", len(content), len(rest), err)
+	}
+
+	if _, _, err := rlp.SplitString([]byte{0xb8, 0x03, 'd', 'o', 'g'}); !errors.Is(err, rlp.ErrCanonSize) {
+		t.Errorf("expected ErrCanonSize, got %v", err)
+	}
+
+	if _, _, err := rlp.SplitList([]byte{0xb9, 0x00, 0x40}); !errors.Is(err, rlp.ErrCanonSize) {
+		t.Errorf("expected ErrCanonSize for size with leading zero, got %v", err)
+	}
+
+	if x, _, err := rlp.SplitUint64(rlp.EncodeUint64(1 << 40)); err != nil || x != 1<<40 {
+		t.Errorf("expected %d, got %d (%v)", uint64(1<<40), x, err)
+	}
+}
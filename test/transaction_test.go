@@ -0,0 +1,258 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func TestEffectiveFees(t *testing.T) {
+	tx := &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		ChainID:              1,
+		MaxPriorityFeePerGas: uint256.NewInt(2_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(5_000_000_000),
+	}
+
+	tests := []struct {
+		name        string
+		baseFee     uint64
+		expectedTip uint64
+	}{
+		{name: "full tip", baseFee: 1_000_000_000, expectedTip: 2_000_000_000},
+		{name: "tip capped by fee cap", baseFee: 4_000_000_000, expectedTip: 1_000_000_000},
+		{name: "fee cap equals base fee", baseFee: 5_000_000_000, expectedTip: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseFee := uint256.NewInt(tt.baseFee)
+
+			tip, err := tx.EffectivePriorityFee(baseFee)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tip != uint256.NewInt(tt.expectedTip) {
+				t.Errorf("expected tip %d, got %s", tt.expectedTip, tip)
+			}
+
+			price, err := tx.EffectiveGasPrice(baseFee)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if price != uint256.NewInt(tt.expectedTip+tt.baseFee) {
+				t.Errorf("expected gas price %d, got %s", tt.expectedTip+tt.baseFee, price)
+			}
+		})
+	}
+}
+
+func TestEffectiveFeesBelowBaseFee(t *testing.T) {
+	tx := &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		ChainID:              1,
+		MaxPriorityFeePerGas: uint256.NewInt(2_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(1_000_000_000),
+	}
+	baseFee := uint256.NewInt(1_000_000_001)
+
+	if _, err := tx.EffectivePriorityFee(baseFee); !errors.Is(err, types.ErrFeeCapBelowBaseFee) {
+		t.Errorf("expected ErrFeeCapBelowBaseFee, got %v", err)
+	}
+
+	if _, err := tx.EffectiveGasPrice(baseFee); !errors.Is(err, types.ErrFeeCapBelowBaseFee) {
+		t.Errorf("expected ErrFeeCapBelowBaseFee, got %v", err)
+	}
+}
+
+func TestMaxCostOverflow(t *testing.T) {
+	tx := &types.Transaction{
+		Type:         types.DynamicFeeTxType,
+		ChainID:      1,
+		MaxFeePerGas: uint256.Max,
+		GasLimit:     2,
+	}
+
+	if _, err := tx.MaxCost(); !errors.Is(err, types.ErrWeiOverflow) {
+		t.Errorf("expected ErrWeiOverflow, got %v", err)
+	}
+
+	tx = &types.Transaction{
+		Type:         types.DynamicFeeTxType,
+		ChainID:      1,
+		MaxFeePerGas: uint256.NewInt(1),
+		GasLimit:     1,
+		Value:        uint256.Max,
+	}
+
+	if _, err := tx.MaxCost(); !errors.Is(err, types.ErrWeiOverflow) {
+		t.Errorf("expected ErrWeiOverflow, got %v", err)
+	}
+}
+
+func TestSafeGasMath(t *testing.T) {
+	if _, err := types.SafeAddGas(^uint64(0), 1); !errors.Is(err, types.ErrGasUintOverflow) {
+		t.Errorf("expected ErrGasUintOverflow, got %v", err)
+	}
+
+	if _, err := types.SafeMulGas(1<<32, 1<<32); !errors.Is(err, types.ErrGasUintOverflow) {
+		t.Errorf("expected ErrGasUintOverflow, got %v", err)
+	}
+
+	if _, err := types.SafeSubGas(1, 2); !errors.Is(err, types.ErrGasUintUnderflow) {
+		t.Errorf("expected ErrGasUintUnderflow, got %v", err)
+	}
+
+	if v, err := types.SafeMulGas(0, ^uint64(0)); err != nil || v != 0 {
+		t.Errorf("expected 0, got %d (%v)", v, err)
+	}
+}
+
+func TestExecuteTransactionRejectsFeeCapBelowBaseFee(t *testing.T) {
+	state := types.NewState()
+	initialBalance := uint256.NewInt(1_000_000_000_000_000)
+	state.SetAccount(alice, types.NewAccount(alice, initialBalance))
+
+	block := &types.Block{
+		Number:   1,
+		GasLimit: 30_000_000,
+		BaseFee:  uint256.NewInt(2_000_000_000),
+		Miner:    miner,
+	}
+
+	tx := &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		ChainID:              1,
+		From:                 alice,
+		To:                   &bob,
+		MaxPriorityFeePerGas: uint256.NewInt(1_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(1_000_000_000),
+		GasLimit:             21_000,
+	}
+
+	result := executor.ExecuteTransaction(tx, block, state)
+	if result.Success {
+		t.Fatal("transaction should fail when fee cap is below base fee")
+	}
+	if !errors.Is(result.Error, types.ErrFeeCapBelowBaseFee) {
+		t.Errorf("expected ErrFeeCapBelowBaseFee, got %v", result.Error)
+	}
+
+	// Nothing should have been charged
+	if got := state.GetBalance(alice); got != initialBalance {
+		t.Errorf("expected Alice balance %s, got %s", initialBalance, got)
+	}
+	if got := state.GetBalance(miner); !got.IsZero() {
+		t.Errorf("expected miner balance 0, got %s", got)
+	}
+}
+
+func TestExecuteTransactionRejectsGasAboveLimit(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+
+	block := &types.Block{
+		Number:   1,
+		GasLimit: 30_000_000,
+		BaseFee:  uint256.NewInt(1_000_000_000),
+		Miner:    miner,
+	}
+
+	// 1 KiB of calldata needs more than the 21000 gas this transaction offers;
+	// the executor refuses it instead of underflowing the refund
+	tx := &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		ChainID:              1,
+		From:                 alice,
+		To:                   &bob,
+		MaxPriorityFeePerGas: uint256.NewInt(1_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(2_000_000_000),
+		GasLimit:             21_000,
+		Data:                 make([]byte, 1024),
+	}
+
+	result := executor.ExecuteTransaction(tx, block, state)
+	if result.Success {
+		t.Fatal("transaction should fail when gas used exceeds its gas limit")
+	}
+	var gasErr *types.IntrinsicGasError
+	if !errors.As(result.Error, &gasErr) || gasErr.IntrinsicGas != 21_000+1024*4 {
+		t.Errorf("expected IntrinsicGasError for 25096 gas, got %v", result.Error)
+	}
+	if state.GetNonce(alice) != 0 {
+		t.Errorf("expected Alice nonce 0, got %d", state.GetNonce(alice))
+	}
+}
+
+func TestIntrinsicGas(t *testing.T) {
+	tests := []struct {
+		name string
+		to   *types.Address
+		data []byte
+		want uint64
+	}{
+		{name: "transfer", to: &bob, want: 21_000},
+		{name: "zero bytes", to: &bob, data: make([]byte, 10), want: 21_000 + 10*4},
+		{name: "mixed bytes", to: &bob, data: []byte{0, 1, 0, 0xff}, want: 21_000 + 2*4 + 2*16},
+		{name: "empty creation", want: 53_000},
+		// 33 bytes of initcode round up to 2 words
+		{name: "creation", data: bytes.Repeat([]byte{0x60}, 33), want: 53_000 + 33*16 + 2*2},
+	}
+
+	for _, tt := range tests {
+		tx := &types.Transaction{To: tt.to, Data: tt.data}
+		got, err := tx.IntrinsicGas()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestValidateTransactionIntrinsicGas(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000_000)))
+	header := &types.Block{BaseFee: uint256.NewInt(1_000_000_000)}
+	newTx := func(to *types.Address, gasLimit uint64, data []byte) *types.Transaction {
+		return signTx(t, &types.Transaction{
+			Type:                 types.DynamicFeeTxType,
+			ChainID:              1,
+			From:                 alice,
+			To:                   to,
+			MaxPriorityFeePerGas: uint256.NewInt(1),
+			MaxFeePerGas:         uint256.NewInt(2_000_000_000),
+			GasLimit:             gasLimit,
+			Data:                 data,
+		}, aliceKey)
+	}
+
+	// Kilobytes of calldata under a transfer's gas limit are rejected up front
+	var gasErr *types.IntrinsicGasError
+	err := validator.ValidateTransaction(constants.MainnetConfig, newTx(&bob, 21_000, bytes.Repeat([]byte{1}, 4096)), header, state)
+	if !errors.As(err, &gasErr) || gasErr.IntrinsicGas != 21_000+4096*16 {
+		t.Errorf("expected IntrinsicGasError, got %v", err)
+	}
+
+	// Creation costs 53000 before any initcode
+	if err := validator.ValidateTransaction(constants.MainnetConfig, newTx(nil, 21_000, nil), header, state); !errors.Is(err, types.ErrIntrinsicGas) {
+		t.Errorf("expected ErrIntrinsicGas for a creation, got %v", err)
+	}
+	if err := validator.ValidateTransaction(constants.MainnetConfig, newTx(nil, 53_000, nil), header, state); err != nil {
+		t.Errorf("expected a 53000 gas creation to be valid, got %v", err)
+	}
+
+	// EIP-3860 caps initcode, however much gas is offered
+	var sizeErr *types.InitCodeSizeError
+	err = validator.ValidateTransaction(constants.MainnetConfig, newTx(nil, 10_000_000, make([]byte, constants.MaxInitCodeSize+1)), header, state)
+	if !errors.As(err, &sizeErr) || sizeErr.Size != constants.MaxInitCodeSize+1 {
+		t.Errorf("expected InitCodeSizeError, got %v", err)
+	}
+}
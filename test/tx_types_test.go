@@ -0,0 +1,181 @@
+package test
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func TestLegacyTransactionEIP155Vector(t *testing.T) {
+	// The example transaction from EIP-155
+	to := types.HexToAddress("0x3535353535353535353535353535353535353535")
+	tx := signTx(t, &types.Transaction{
+		Type:     types.LegacyTxType,
+		ChainID:  1,
+		Nonce:    9,
+		GasPrice: uint256.NewInt(20_000_000_000),
+		GasLimit: 21_000,
+		To:       &to,
+		Value:    uint256.MustFromDecimal("1000000000000000000"),
+	}, mustKey("0x4646464646464646464646464646464646464646464646464646464646464646"))
+
+	if got := tx.SigningHash().Hex(); got != "0xdaf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53" {
+		t.Errorf("unexpected signing hash %s", got)
+	}
+	if tx.V != uint256.NewInt(37) {
+		t.Errorf("expected v 37, got %s", tx.V)
+	}
+
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	want := "f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+	if got := hex.EncodeToString(enc); got != want {
+		t.Fatalf("unexpected encoding\nhave %s\nwant %s", got, want)
+	}
+
+	raw, _ := hex.DecodeString(want)
+	dec, err := types.DecodeTransaction(raw)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if dec.Type != types.LegacyTxType || dec.ChainID != 1 {
+		t.Errorf("expected legacy transaction on chain 1, got type %d chain %d", dec.Type, dec.ChainID)
+	}
+	sender, err := dec.Sender()
+	if err != nil || sender != tx.From {
+		t.Errorf("expected sender %s, got %s (%v)", tx.From, sender, err)
+	}
+}
+
+func TestTypedAndLegacyRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		tx   *types.Transaction
+	}{
+		{
+			name: "legacy pre-EIP-155",
+			tx: &types.Transaction{
+				Type:     types.LegacyTxType,
+				Nonce:    1,
+				GasPrice: uint256.NewInt(3_000_000_000),
+				GasLimit: 21_000,
+				To:       &bob,
+				Value:    uint256.NewInt(1),
+			},
+		},
+		{
+			name: "access list",
+			tx: &types.Transaction{
+				Type:       types.AccessListTxType,
+				ChainID:    5,
+				Nonce:      2,
+				GasPrice:   uint256.NewInt(3_000_000_000),
+				GasLimit:   30_000,
+				To:         &bob,
+				Data:       []byte{0xca, 0xfe},
+				AccessList: testAccessList,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signTx(t, tt.tx, aliceKey)
+			enc, err := tt.tx.MarshalBinary()
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+
+			dec, err := types.DecodeTransaction(enc)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if dec.Type != tt.tx.Type || dec.ChainID != tt.tx.ChainID || dec.GasPrice != tt.tx.GasPrice {
+				t.Errorf("fields changed across round trip: %+v", dec)
+			}
+			if sender, err := dec.Sender(); err != nil || sender != alice {
+				t.Errorf("expected sender %s, got %s (%v)", alice, sender, err)
+			}
+		})
+	}
+}
+
+func TestGasPriceMapsToFeeCaps(t *testing.T) {
+	baseFee := uint256.NewInt(1_000_000_000)
+	tx := &types.Transaction{Type: types.LegacyTxType, GasPrice: uint256.NewInt(3_000_000_000), GasLimit: 21_000}
+
+	if tx.GasFeeCap() != tx.GasPrice || tx.GasTipCap() != tx.GasPrice {
+		t.Error("gas price should act as both fee cap and tip cap")
+	}
+
+	// The whole gas price is paid; everything above the base fee goes to the miner
+	price, err := tx.EffectiveGasPrice(baseFee)
+	if err != nil || price != tx.GasPrice {
+		t.Errorf("expected effective price %s, got %s (%v)", tx.GasPrice, price, err)
+	}
+	tip, err := tx.EffectivePriorityFee(baseFee)
+	if err != nil || tip != uint256.NewInt(2_000_000_000) {
+		t.Errorf("expected tip 2000000000, got %s (%v)", tip, err)
+	}
+
+	tx.GasPrice = uint256.NewInt(999_999_999)
+	if err := tx.Validate(baseFee); !errors.Is(err, types.ErrFeeCapBelowBaseFee) {
+		t.Errorf("expected ErrFeeCapBelowBaseFee, got %v", err)
+	}
+
+	tx.GasPrice = baseFee
+	tx.AccessList = testAccessList
+	if err := tx.Validate(baseFee); !errors.Is(err, types.ErrMalformedTransaction) {
+		t.Errorf("expected legacy access list rejection, got %v", err)
+	}
+}
+
+func TestLegacySignatureChainMismatch(t *testing.T) {
+	tx := signTx(t, &types.Transaction{Type: types.LegacyTxType, ChainID: 1, GasPrice: uint256.NewInt(1), GasLimit: 21_000}, aliceKey)
+
+	tx.ChainID = 2
+	var sigErr *types.InvalidSignatureError
+	if _, err := tx.Sender(); !errors.As(err, &sigErr) {
+		t.Errorf("expected InvalidSignatureError, got %v", err)
+	}
+}
+
+func TestMixedTransactionTypesInBlock(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+	state.SetAccount(miner, types.NewAccount(miner, uint256.Zero))
+
+	baseFee := uint256.NewInt(1_000_000_000)
+	block := types.NewBlock(1, types.Hash{}, 30_000_000, baseFee, miner)
+	txs := []*types.Transaction{
+		{Type: types.LegacyTxType, ChainID: 1, Nonce: 0, GasPrice: uint256.NewInt(3_000_000_000), GasLimit: 21_000, To: &bob},
+		{Type: types.AccessListTxType, ChainID: 1, Nonce: 1, GasPrice: uint256.NewInt(3_000_000_000), GasLimit: 30_000, To: &bob},
+		{Type: types.DynamicFeeTxType, ChainID: 1, Nonce: 2, MaxPriorityFeePerGas: uint256.NewInt(2_000_000_000), MaxFeePerGas: uint256.NewInt(5_000_000_000), GasLimit: 21_000, To: &bob},
+	}
+
+	for i, tx := range txs {
+		signTx(t, tx, aliceKey)
+		if err := validator.ValidateTransaction(constants.MainnetConfig, tx, block, state); err != nil {
+			t.Fatalf("tx %d: validation failed: %v", i, err)
+		}
+		result := executor.ExecuteTransaction(tx, block, state)
+		if !result.Success {
+			t.Fatalf("tx %d: execution failed: %v", i, result.Error)
+		}
+		if result.TipAmount != uint256.NewInt(21_000*2_000_000_000) {
+			t.Errorf("tx %d: expected tip %d, got %s", i, 21_000*2_000_000_000, result.TipAmount)
+		}
+	}
+
+	if got := state.GetBalance(miner); got != uint256.NewInt(3*21_000*2_000_000_000) {
+		t.Errorf("unexpected miner balance %s", got)
+	}
+}
@@ -0,0 +1,121 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
+)
+
+func TestUint256Arithmetic(t *testing.T) {
+	maxU64 := uint256.NewInt(^uint64(0))
+
+	sum, overflow := maxU64.AddOverflow(uint256.NewInt(1))
+	if overflow {
+		t.Fatal("2^64 should not overflow 256 bits")
+	}
+	if sum.String() != "18446744073709551616" {
+		t.Errorf("expected 2^64, got %s", sum)
+	}
+
+	product, overflow := maxU64.MulOverflow(maxU64)
+	if overflow {
+		t.Fatal("(2^64-1)^2 should not overflow 256 bits")
+	}
+	want := new(big.Int).Mul(maxU64.ToBig(), maxU64.ToBig())
+	if product.ToBig().Cmp(want) != 0 {
+		t.Errorf("expected %s, got %s", want, product)
+	}
+
+	if quotient := product.Div(maxU64); quotient != maxU64 {
+		t.Errorf("expected %s, got %s", maxU64, quotient)
+	}
+
+	if _, overflow := uint256.Max.AddOverflow(uint256.NewInt(1)); !overflow {
+		t.Error("expected overflow adding 1 to max")
+	}
+
+	if _, overflow := uint256.Max.MulOverflow(uint256.NewInt(2)); !overflow {
+		t.Error("expected overflow doubling max")
+	}
+
+	if _, underflow := uint256.Zero.SubUnderflow(uint256.NewInt(1)); !underflow {
+		t.Error("expected underflow subtracting 1 from 0")
+	}
+}
+
+func TestUint256Conversions(t *testing.T) {
+	v := uint256.MustFromDecimal("115792089237316195423570985008687907853269984665640564039457584007913129639935")
+	if v != uint256.Max {
+		t.Errorf("expected max, got %s", v)
+	}
+
+	if _, err := uint256.FromDecimal("115792089237316195423570985008687907853269984665640564039457584007913129639936"); err == nil {
+		t.Error("expected overflow error for 2^256")
+	}
+
+	x := uint256.MustFromDecimal("340282366920938463463374607431768211457") // 2^128 + 1
+	if got := uint256.FromBytes(x.Bytes()); got != x {
+		t.Errorf("bytes round trip: expected %s, got %s", x, got)
+	}
+}
+
+func TestMainnetScaleBalances(t *testing.T) {
+	// 10,000 ETH is far beyond what a uint64 can hold in wei
+	balance := uint256.MustFromDecimal("10000000000000000000000")
+
+	state := types.NewState()
+	state.SetAccount(whale, types.NewAccount(whale, balance))
+	state.SetAccount(miner, types.NewAccount(miner, uint256.Zero))
+
+	// A 5,000 gwei fee spike
+	block := &types.Block{
+		Number:   constants.ForkBlockNumber + 1,
+		GasLimit: 30_000_000,
+		BaseFee:  uint256.NewInt(5_000_000_000_000),
+		Miner:    miner,
+	}
+
+	// Sending 1,000 ETH
+	value := uint256.MustFromDecimal("1000000000000000000000")
+	tx := &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		From:                 whale,
+		To:                   &bob,
+		MaxPriorityFeePerGas: uint256.NewInt(1_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(10_000_000_000_000),
+		GasLimit:             21_000,
+		Value:                value,
+	}
+
+	result := executor.ExecuteTransaction(tx, block, state)
+	if !result.Success {
+		t.Fatalf("transaction should succeed, got error: %v", result.Error)
+	}
+
+	if got := state.GetBalance(bob); got != value {
+		t.Errorf("expected Bob balance %s, got %s", value, got)
+	}
+
+	spent := uint256.NewInt(21_000).Mul(uint256.NewInt(5_001_000_000_000)).Add(value)
+	if got := state.GetBalance(whale); got != balance.Sub(spent) {
+		t.Errorf("expected whale balance %s, got %s", balance.Sub(spent), got)
+	}
+
+	// Base fee math must not wrap on very large base fees either
+	parent := &types.Block{
+		Number:   constants.ForkBlockNumber,
+		GasLimit: 30_000_000,
+		GasUsed:  30_000_000,
+		BaseFee:  uint256.MustFromDecimal("100000000000000000000000"),
+	}
+	next := basefee.Calculate(constants.MainnetConfig, parent)
+	expected := uint256.MustFromDecimal("112500000000000000000000")
+	if next != expected {
+		t.Errorf("expected base fee %s, got %s", expected, next)
+	}
+}
@@ -1,95 +1,121 @@
 package test
 
 import (
+	"errors"
+	"math"
 	"testing"
 
+	"github.com/EIPs-CodeLab/EIP-1559/internal/basefee"
+	"github.com/EIPs-CodeLab/EIP-1559/internal/executor"
 	"github.com/EIPs-CodeLab/EIP-1559/internal/types"
 	"github.com/EIPs-CodeLab/EIP-1559/internal/validator"
 	"github.com/EIPs-CodeLab/EIP-1559/pkg/constants"
+	"github.com/EIPs-CodeLab/EIP-1559/pkg/uint256"
 )
 
 func TestValidateTransaction(t *testing.T) {
 	state := types.NewState()
 	// Give Alice sufficient upfront funds to cover max-fee * gas for test transactions
-	state.SetAccount("0xAlice", types.NewAccount("0xAlice", 200_000_000_000_000))
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(200_000_000_000_000)))
 
-	baseFee := uint64(1_000_000_000)
+	header := &types.Block{BaseFee: uint256.NewInt(1_000_000_000)}
 
 	tests := []struct {
 		name    string
 		tx      *types.Transaction
 		wantErr bool
+		errIs   error
 		errMsg  string
 	}{
 		{
 			name: "valid transaction",
 			tx: &types.Transaction{
-				From:                 "0xAlice",
+				Type:                 types.DynamicFeeTxType,
+				ChainID:              1,
+				From:                 alice,
+				To:                   &bob,
 				Nonce:                0,
-				MaxPriorityFeePerGas: 2_000_000_000,
-				MaxFeePerGas:         5_000_000_000,
+				MaxPriorityFeePerGas: uint256.NewInt(2_000_000_000),
+				MaxFeePerGas:         uint256.NewInt(5_000_000_000),
 				GasLimit:             21_000,
-				Value:                1_000,
+				Value:                uint256.NewInt(1_000),
 			},
 			wantErr: false,
 		},
 		{
 			name: "max fee less than base fee",
 			tx: &types.Transaction{
-				From:                 "0xAlice",
+				Type:                 types.DynamicFeeTxType,
+				ChainID:              1,
+				From:                 alice,
+				To:                   &bob,
 				Nonce:                0,
-				MaxPriorityFeePerGas: 500_000_000,
-				MaxFeePerGas:         500_000_000, // Less than base fee
+				MaxPriorityFeePerGas: uint256.NewInt(500_000_000),
+				MaxFeePerGas:         uint256.NewInt(500_000_000), // Less than base fee
 				GasLimit:             21_000,
-				Value:                1_000,
+				Value:                uint256.NewInt(1_000),
 			},
 			wantErr: true,
+			errIs:   types.ErrFeeCapBelowBaseFee,
 			errMsg:  "less than base fee",
 		},
 		{
 			name: "max fee less than priority fee",
 			tx: &types.Transaction{
-				From:                 "0xAlice",
+				Type:                 types.DynamicFeeTxType,
+				ChainID:              1,
+				From:                 alice,
+				To:                   &bob,
 				Nonce:                0,
-				MaxPriorityFeePerGas: 5_000_000_000,
-				MaxFeePerGas:         2_000_000_000, // Less than priority fee
+				MaxPriorityFeePerGas: uint256.NewInt(5_000_000_000),
+				MaxFeePerGas:         uint256.NewInt(2_000_000_000), // Less than priority fee
 				GasLimit:             21_000,
-				Value:                1_000,
+				Value:                uint256.NewInt(1_000),
 			},
 			wantErr: true,
+			errIs:   types.ErrTipAboveFeeCap,
 			errMsg:  "less than max priority fee",
 		},
 		{
 			name: "insufficient balance",
 			tx: &types.Transaction{
-				From:                 "0xBob", // No balance
+				Type:                 types.DynamicFeeTxType,
+				ChainID:              1,
+				From:                 bob, // No balance
+				To:                   &carol,
 				Nonce:                0,
-				MaxPriorityFeePerGas: 2_000_000_000,
-				MaxFeePerGas:         5_000_000_000,
+				MaxPriorityFeePerGas: uint256.NewInt(2_000_000_000),
+				MaxFeePerGas:         uint256.NewInt(5_000_000_000),
 				GasLimit:             21_000,
-				Value:                1_000,
+				Value:                uint256.NewInt(1_000),
 			},
 			wantErr: true,
+			errIs:   types.ErrInsufficientFunds,
 			errMsg:  "insufficient funds",
 		},
 		{
 			name: "invalid nonce",
 			tx: &types.Transaction{
-				From:                 "0xAlice",
+				Type:                 types.DynamicFeeTxType,
+				ChainID:              1,
+				From:                 alice,
+				To:                   &bob,
 				Nonce:                5, // Wrong nonce
-				MaxPriorityFeePerGas: 2_000_000_000,
-				MaxFeePerGas:         5_000_000_000,
+				MaxPriorityFeePerGas: uint256.NewInt(2_000_000_000),
+				MaxFeePerGas:         uint256.NewInt(5_000_000_000),
 				GasLimit:             21_000,
-				Value:                1_000,
+				Value:                uint256.NewInt(1_000),
 			},
 			wantErr: true,
+			errIs:   types.ErrNonceTooHigh,
 			errMsg:  "invalid nonce",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validator.ValidateTransaction(tt.tx, baseFee, state)
+			signTx(t, tt.tx, testKeys[tt.tx.From])
+			err := validator.ValidateTransaction(constants.MainnetConfig, tt.tx, header, state)
 
 			if tt.wantErr && err == nil {
 				t.Errorf("expected error containing '%s', got nil", tt.errMsg)
@@ -100,6 +126,10 @@ func TestValidateTransaction(t *testing.T) {
 			}
 
 			if tt.wantErr && err != nil {
+				if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+					t.Errorf("expected error matching %v, got %v", tt.errIs, err)
+				}
+
 				// Check error message contains expected text
 				if tt.errMsg != "" && !contains(err.Error(), tt.errMsg) {
 					t.Errorf("expected error containing '%s', got '%s'", tt.errMsg, err.Error())
@@ -112,28 +142,30 @@ func TestValidateTransaction(t *testing.T) {
 func TestValidateBlock(t *testing.T) {
 	parent := &types.Block{
 		Number:   constants.ForkBlockNumber,
-		Hash:     "0xparent",
 		GasLimit: 30_000_000,
 		GasUsed:  15_000_000,
-		BaseFee:  1_000_000_000,
+		BaseFee:  uint256.NewInt(1_000_000_000),
 	}
 
 	tests := []struct {
 		name    string
 		block   *types.Block
 		wantErr bool
+		errIs   error
 		errMsg  string
 	}{
 		{
 			name: "valid block",
 			block: &types.Block{
 				Number:     parent.Number + 1,
-				ParentHash: parent.Hash,
+				ParentHash: parent.Hash(),
+				Miner:      miner,
+				Timestamp:  parent.Timestamp + 12,
 				GasLimit:   30_000_000,
 				GasUsed:    20_000_000,
 				// Base fee is calculated from the parent block's usage; parent used exactly target
 				// so base fee should remain unchanged.
-				BaseFee:    1_000_000_000,
+				BaseFee: uint256.NewInt(1_000_000_000),
 			},
 			wantErr: false,
 		},
@@ -141,55 +173,123 @@ func TestValidateBlock(t *testing.T) {
 			name: "invalid block number",
 			block: &types.Block{
 				Number:     parent.Number + 2, // Skip a block
-				ParentHash: parent.Hash,
+				ParentHash: parent.Hash(),
+				Miner:      miner,
+				Timestamp:  parent.Timestamp + 12,
 				GasLimit:   30_000_000,
 				GasUsed:    15_000_000,
-				BaseFee:    1_000_000_000,
+				BaseFee:    uint256.NewInt(1_000_000_000),
 			},
 			wantErr: true,
+			errIs:   validator.ErrInvalidBlockNumber,
 			errMsg:  "invalid block number",
 		},
 		{
 			name: "gas used exceeds limit",
 			block: &types.Block{
 				Number:     parent.Number + 1,
-				ParentHash: parent.Hash,
+				ParentHash: parent.Hash(),
+				Miner:      miner,
+				Timestamp:  parent.Timestamp + 12,
 				GasLimit:   30_000_000,
 				GasUsed:    31_000_000, // Exceeds limit
-				BaseFee:    1_000_000_000,
+				BaseFee:    uint256.NewInt(1_000_000_000),
 			},
 			wantErr: true,
+			errIs:   types.ErrGasLimitExceeded,
 			errMsg:  "exceeds gas limit",
 		},
 		{
 			name: "gas limit increased too much",
 			block: &types.Block{
 				Number:     parent.Number + 1,
-				ParentHash: parent.Hash,
+				ParentHash: parent.Hash(),
+				Miner:      miner,
+				Timestamp:  parent.Timestamp + 12,
 				GasLimit:   35_000_000, // Too much increase
 				GasUsed:    15_000_000,
-				BaseFee:    1_000_000_000,
+				BaseFee:    uint256.NewInt(1_000_000_000),
 			},
 			wantErr: true,
+			errIs:   validator.ErrGasLimitOutOfBounds,
 			errMsg:  "increased too much",
 		},
 		{
 			name: "invalid base fee",
 			block: &types.Block{
 				Number:     parent.Number + 1,
-				ParentHash: parent.Hash,
+				ParentHash: parent.Hash(),
+				Miner:      miner,
+				Timestamp:  parent.Timestamp + 12,
 				GasLimit:   30_000_000,
 				GasUsed:    15_000_000,
-				BaseFee:    2_000_000_000, // Wrong base fee
+				BaseFee:    uint256.NewInt(2_000_000_000), // Wrong base fee
 			},
 			wantErr: true,
+			errIs:   validator.ErrBadBaseFee,
 			errMsg:  "invalid base fee",
 		},
+		{
+			name: "timestamp not after parent",
+			block: &types.Block{
+				Number:     parent.Number + 1,
+				ParentHash: parent.Hash(),
+				Miner:      miner,
+				Timestamp:  parent.Timestamp,
+				GasLimit:   30_000_000,
+				BaseFee:    uint256.NewInt(1_000_000_000),
+			},
+			wantErr: true,
+			errIs:   validator.ErrBadTimestamp,
+			errMsg:  "invalid timestamp",
+		},
+		{
+			name: "extra data too long",
+			block: &types.Block{
+				Number:     parent.Number + 1,
+				ParentHash: parent.Hash(),
+				Miner:      miner,
+				Timestamp:  parent.Timestamp + 12,
+				Extra:      make([]byte, constants.MaxExtraDataSize+1),
+				GasLimit:   30_000_000,
+				BaseFee:    uint256.NewInt(1_000_000_000),
+			},
+			wantErr: true,
+			errIs:   validator.ErrExtraDataTooLong,
+			errMsg:  "33 bytes, max 32",
+		},
+		{
+			name: "zero miner",
+			block: &types.Block{
+				Number:     parent.Number + 1,
+				ParentHash: parent.Hash(),
+				Timestamp:  parent.Timestamp + 12,
+				GasLimit:   30_000_000,
+				BaseFee:    uint256.NewInt(1_000_000_000),
+			},
+			wantErr: true,
+			errIs:   validator.ErrZeroMiner,
+		},
+		{
+			name: "transaction count does not match body",
+			block: &types.Block{
+				Number:     parent.Number + 1,
+				ParentHash: parent.Hash(),
+				Miner:      miner,
+				Timestamp:  parent.Timestamp + 12,
+				TxCount:    1,
+				GasLimit:   30_000_000,
+				BaseFee:    uint256.NewInt(1_000_000_000),
+			},
+			wantErr: true,
+			errIs:   validator.ErrBadTxCount,
+			errMsg:  "header 1, body 0",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validator.ValidateBlock(tt.block, parent)
+			err := validator.ValidateBlock(constants.MainnetConfig, tt.block, parent, testNow)
 
 			if tt.wantErr && err == nil {
 				t.Errorf("expected error, got nil")
@@ -199,6 +299,10 @@ func TestValidateBlock(t *testing.T) {
 				t.Errorf("expected no error, got %v", err)
 			}
 
+			if tt.wantErr && err != nil && tt.errIs != nil && !errors.Is(err, tt.errIs) {
+				t.Errorf("expected error matching %v, got %v", tt.errIs, err)
+			}
+
 			if tt.wantErr && err != nil && tt.errMsg != "" {
 				if !contains(err.Error(), tt.errMsg) {
 					t.Errorf("expected error containing '%s', got '%s'", tt.errMsg, err.Error())
@@ -208,6 +312,102 @@ func TestValidateBlock(t *testing.T) {
 	}
 }
 
+func TestValidateReceipts(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+
+	block := types.NewBlock(1, types.Hash{}, 30_000_000, uint256.NewInt(1_000_000_000), miner)
+	tx := &types.Transaction{
+		Type:                 types.DynamicFeeTxType,
+		ChainID:              1,
+		From:                 alice,
+		To:                   &bob,
+		MaxPriorityFeePerGas: uint256.NewInt(1_000_000_000),
+		MaxFeePerGas:         uint256.NewInt(5_000_000_000),
+		GasLimit:             100_000,
+	}
+	if err := block.AddTransaction(tx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results, err := executor.ExecuteBlock(block, state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// GasUsed still holds the 100k reserved by AddTransaction, not the 21k executed
+	var gasErr *validator.GasUsedError
+	if err := validator.ValidateReceipts(block, results); !errors.As(err, &gasErr) || gasErr.Expected != 21_000 || gasErr.Got != 100_000 {
+		t.Errorf("expected GasUsedError 21000 vs 100000, got %v", err)
+	}
+
+	block.GasUsed = 21_000
+	if err := validator.ValidateReceipts(block, results); err != nil {
+		t.Errorf("expected receipts to match, got %v", err)
+	}
+	if err := validator.ValidateReceipts(block, nil); !errors.Is(err, validator.ErrBadReceiptCount) {
+		t.Errorf("expected ErrBadReceiptCount, got %v", err)
+	}
+}
+
+func TestValidateBlockTime(t *testing.T) {
+	const now = 1_700_000_000
+	block := &types.Block{Timestamp: now + constants.AllowedFutureBlockTime}
+	if err := validator.ValidateBlockTime(block, now); err != nil {
+		t.Errorf("expected a block %ds ahead to be valid, got %v", constants.AllowedFutureBlockTime, err)
+	}
+
+	block.Timestamp++
+	var futureErr *validator.FutureBlockError
+	if err := validator.ValidateBlockTime(block, now); !errors.As(err, &futureErr) || futureErr.Now != now {
+		t.Errorf("expected FutureBlockError, got %v", err)
+	}
+
+	// A clock near the top of the range does not wrap around
+	if err := validator.ValidateBlockTime(&types.Block{Timestamp: math.MaxUint64}, math.MaxUint64-1); err != nil {
+		t.Errorf("expected a block 1s ahead to be valid, got %v", err)
+	}
+
+	// Header validation runs the check against the clock it is given
+	parent := &types.Block{Number: 99, GasLimit: 30_000_000}
+	block = types.NewBlock(100, parent.Hash(), 30_000_000, uint256.NewInt(0), miner)
+	block.Timestamp = now + constants.AllowedFutureBlockTime + 1
+	if err := validator.ValidateBlock(constants.MainnetConfig, block, parent, now); !errors.As(err, &futureErr) || !errors.Is(err, validator.ErrFutureBlock) {
+		t.Errorf("expected FutureBlockError from ValidateBlock, got %v", err)
+	}
+	if err := validator.ValidateBlock(constants.MainnetConfig, block, parent, block.Timestamp); err != nil {
+		t.Errorf("expected the block to be valid once the clock catches up, got %v", err)
+	}
+}
+
+func TestValidateExecutedBlock(t *testing.T) {
+	state := types.NewState()
+	state.SetAccount(alice, types.NewAccount(alice, uint256.NewInt(1_000_000_000_000_000)))
+	parent := &types.Block{Number: constants.ForkBlockNumber + 1, GasLimit: 30_000_000, GasUsed: 15_000_000, BaseFee: uint256.NewInt(1_000_000_000), Miner: miner}
+
+	block := types.NewBlock(parent.Number+1, parent.Hash(), 30_000_000, uint256.NewInt(1_000_000_000), miner)
+	block.Timestamp = parent.Timestamp + 12
+	if err := block.AddTransaction(signTx(t, newTransfer(1), aliceKey)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results, err := executor.ExecuteBlock(block, state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A block whose receipts disagree with its header fails even though the header is valid
+	block.GasUsed = 20_000
+	if err := validator.ValidateExecutedBlock(constants.MainnetConfig, basefee.DefaultRule, block, parent, testNow, results); !errors.Is(err, validator.ErrBadGasUsed) {
+		t.Errorf("expected ErrBadGasUsed, got %v", err)
+	}
+
+	if err := executor.SealBlock(block, results); err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if err := validator.ValidateExecutedBlock(constants.MainnetConfig, basefee.DefaultRule, block, parent, testNow, results); err != nil {
+		t.Errorf("expected valid executed block, got %v", err)
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
 		(len(s) > 0 && len(substr) > 0 && containsSubstring(s, substr)))